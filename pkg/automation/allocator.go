@@ -19,6 +19,13 @@ type Allocator struct {
 	Protocol   Protocol
 	DeviceID   string
 
+	// Device is the device given in the inline form, if any
+	Device *model.Device
+
+	// Profile is the name of the browser profile declared in the workspace,
+	// whose settings apply where options are not otherwise set
+	Profile string
+
 	// Options carries the union of exec/remote allocator options. Fields
 	// that do not pertain to the selected allocator produce a warning on
 	// stderr when the context is created.
@@ -35,18 +42,29 @@ func (a *Allocator) SetProtocol(e Protocol) error {
 	return nil
 }
 
+// SetDeviceID sets the device to emulate, which is either the ID of a device
+// or a device given in the inline form, as in width=412,height=915,mobile.
+// Devices declared by the workspace are resolved when the automation runs.
 func (a *Allocator) SetDeviceID(v string) error {
 	a.DeviceID = v
+	a.Device = nil
 
-	if v != "" {
-		_, ok := a.resolveDevice()
-		if !ok {
-			fmt.Fprintf(os.Stderr, "warning: device %q not found\n", a.DeviceID)
+	if model.IsInlineDevice(v) {
+		dev, err := model.ParseDevice(v)
+		if err != nil {
+			return err
 		}
+		a.Device = &dev
 	}
 	return nil
 }
 
+// SetProfile selects the browser profile declared in the workspace.
+func (a *Allocator) SetProfile(v string) error {
+	a.Profile = v
+	return nil
+}
+
 // ensureOptions lazily initializes the allocator options.
 func (a *Allocator) ensureOptions() *AllocatorOptions {
 	if a.Options == nil {
@@ -148,21 +166,45 @@ func (a *Allocator) SetFlag(v []string) error {
 	return nil
 }
 
+// withBrowser gets a copy of the allocator in which the settings of the
+// browser profile apply to options that were not set explicitly.
+func (a *Allocator) withBrowser(b *model.Browser) *Allocator {
+	if b == nil {
+		return a
+	}
+	res := *a
+	if res.BrowserURL == "" && b.RemoteURL != nil {
+		res.BrowserURL = *b.RemoteURL
+	}
+	res.Options = res.ensureOptions().withDefaults(b)
+	return &res
+}
+
 func (a *Allocator) newContext(parent context.Context) (context.Context, context.CancelFunc, error) {
-	ctx := withEvalContext(parent)
 	eng := a.Protocol
 	if eng == nil {
 		eng = ProtocolChromedp
 	}
 
 	if a.BrowserURL != "" {
-		return eng.NewRemoteAllocator(ctx, a.BrowserURL, a.Options)
+		return eng.NewRemoteAllocator(parent, a.BrowserURL, a.Options)
 	}
 
-	return eng.NewExecAllocator(ctx, a.Options)
+	return eng.NewExecAllocator(parent, a.Options)
 }
 
-func (a *Allocator) resolveDevice() (dev model.Device, ok bool) {
-	dev, ok = model.LookupDevice(a.DeviceID)
+// resolveDevice gets the device to emulate, which can be declared by the model.
+// A warning is printed when the device cannot be found.
+func (a *Allocator) resolveDevice(m *model.Model) (dev model.Device, ok bool) {
+	if a.Device != nil {
+		return *a.Device, true
+	}
+	if a.DeviceID == "" {
+		return
+	}
+	dev, ok = m.LookupDevice(a.DeviceID)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "warning: device %q not found\n", a.DeviceID)
+	}
 	return
 }
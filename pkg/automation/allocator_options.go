@@ -5,8 +5,11 @@
 package automation
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/model"
@@ -119,6 +122,32 @@ func (o *AllocatorOptions) execOptions() []chromedp.ExecAllocatorOption {
 	return opts
 }
 
+// withDefaults gets a copy of the options in which unset options take their
+// values from the browser profile. Environment variables of the profile are
+// passed before explicit ones, and explicit flags replace those of the same
+// name.
+func (o *AllocatorOptions) withDefaults(b *model.Browser) *AllocatorOptions {
+	res := *o
+	res.ExecPath = cmp.Or(o.ExecPath, b.ExecPath)
+	res.WSURLReadTimeout = cmp.Or(o.WSURLReadTimeout, b.WSURLReadTimeout)
+	res.UserDataDir = cmp.Or(o.UserDataDir, b.UserDataDir)
+	res.ProxyServer = cmp.Or(o.ProxyServer, b.ProxyServer)
+	res.UserAgent = cmp.Or(o.UserAgent, b.UserAgent)
+	res.WindowSize = cmp.Or(o.WindowSize, b.WindowSize)
+	res.IgnoreCertErrors = cmp.Or(o.IgnoreCertErrors, b.IgnoreCertErrors)
+	res.NoSandbox = cmp.Or(o.NoSandbox, b.NoSandbox)
+	res.Headless = cmp.Or(o.Headless, b.Headless)
+	res.DisableGPU = cmp.Or(o.DisableGPU, b.DisableGPU)
+	res.NoModifyURL = cmp.Or(o.NoModifyURL, b.NoModifyURL)
+	res.Env = slices.Concat(b.Env, o.Env)
+
+	if len(b.Flags) > 0 {
+		res.Flags = maps.Clone(b.Flags)
+		maps.Copy(res.Flags, o.Flags)
+	}
+	return &res
+}
+
 func (o *AllocatorOptions) remoteOptions() []chromedp.RemoteAllocatorOption {
 	var opts []chromedp.RemoteAllocatorOption
 	if boolValue(o.NoModifyURL) {
@@ -0,0 +1,160 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// bindAssert produces a task that checks the state of the page, which fails
+// when any of the checks of the assertion do not hold
+func bindAssert(t *model.Assert) Task {
+	return TaskFunc(func(c context.Context) error {
+		failures, err := assertionFailures(c, t)
+		if err != nil {
+			return err
+		}
+		if len(failures) > 0 {
+			return assertionError(c, t, failures)
+		}
+		return printf("Assertion passed").Do(c)
+	})
+}
+
+func assertionFailures(c context.Context, t *model.Assert) ([]string, error) {
+	var failures []string
+
+	if t.Condition != nil {
+		ok, err := evalCondition(c, t.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			failures = append(failures, "condition is false")
+		}
+	}
+
+	if t.Title != nil || t.TitleMatches != nil {
+		var title string
+		if err := chromedp.Title(&title).Do(c); err != nil {
+			return nil, err
+		}
+
+		if t.Title != nil {
+			want, err := evalString(c, t.Title)
+			if err != nil {
+				return nil, err
+			}
+			if title != want {
+				failures = append(failures, fmt.Sprintf("title is %q, expected %q", title, want))
+			}
+		}
+
+		if t.TitleMatches != nil {
+			pattern, err := evalString(c, t.TitleMatches)
+			if err != nil {
+				return nil, err
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid title_matches: %w", err)
+			}
+			if !re.MatchString(title) {
+				failures = append(failures, fmt.Sprintf("title %q does not match %q", title, pattern))
+			}
+		}
+	}
+
+	if t.Count != nil {
+		want, err := evalInt(c, t.Count)
+		if err != nil {
+			return nil, err
+		}
+		items, err := queryNodeItems(t.Selectors, t.Options)(c)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) != want {
+			failures = append(failures, fmt.Sprintf("found %d elements, expected %d", len(items), want))
+		}
+	}
+
+	if t.TextContains != nil {
+		want, err := evalString(c, t.TextContains)
+		if err != nil {
+			return nil, err
+		}
+		var text string
+		err = bindSelector(func(sel any, opts ...chromedp.QueryOption) chromedp.QueryAction {
+			return chromedp.Text(sel, &text, opts...)
+		}, t.Selectors, t.Options).Do(c)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(text, want) {
+			failures = append(failures, fmt.Sprintf("text %q does not contain %q", text, want))
+		}
+	}
+
+	if t.Attribute != "" {
+		var (
+			value string
+			found bool
+		)
+		err := bindSelector(func(sel any, opts ...chromedp.QueryOption) chromedp.QueryAction {
+			return chromedp.AttributeValue(sel, t.Attribute, &value, &found, opts...)
+		}, t.Selectors, t.Options).Do(c)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case !found:
+			failures = append(failures, fmt.Sprintf("attribute %q is not present", t.Attribute))
+		case t.Value != nil:
+			want, err := evalString(c, t.Value)
+			if err != nil {
+				return nil, err
+			}
+			if value != want {
+				failures = append(failures, fmt.Sprintf("attribute %q is %q, expected %q", t.Attribute, value, want))
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+// assertionError reports the failures of the assertion, which is a diagnostic
+// when the assertion was declared in a file
+func assertionError(c context.Context, t *model.Assert, failures []string) error {
+	message := strings.Join(failures, "; ")
+	if t.Message != nil {
+		custom, err := evalString(c, t.Message)
+		if err != nil {
+			return err
+		}
+		message = custom + " (" + message + ")"
+	}
+
+	if t.DeclRange.Filename == "" {
+		return fmt.Errorf("assertion failed: %s", message)
+	}
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Assertion failed",
+			Detail:   message,
+			Subject:  t.DeclRange.Ptr(),
+		},
+	}
+}
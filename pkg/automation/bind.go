@@ -7,9 +7,12 @@ package automation
 import (
 	"context"
 	"errors"
+	"maps"
+	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/model"
 	"github.com/chromedp/chromedp"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Option provides an option to the automation builder
@@ -51,7 +54,7 @@ func Bind(m *model.Model, opts ...Option) (*Driver, error) {
 	// TODO Don't eagerly build these automations; should be handled
 	// within Driver.flow and possibly cache
 	for _, auto := range m.Automations {
-		automations[auto.Name], err = b.buildAutomation(auto)
+		automations[auto.QualifiedName()], err = b.buildAutomation(auto)
 		if err != nil {
 			return nil, err
 		}
@@ -122,6 +125,40 @@ func WithAllocator(v *Allocator) Option {
 	})
 }
 
+// WithVariables provides values for the input variables declared in the
+// model. Values are converted to the type of the corresponding variable.
+func WithVariables(values map[string]cty.Value) Option {
+	return optionFunc(func(a *Driver) {
+		if a.variables == nil {
+			a.variables = map[string]cty.Value{}
+		}
+		maps.Copy(a.variables, values)
+	})
+}
+
+// WithTimeout limits the time that an execution of an automation can take
+func WithTimeout(d time.Duration) Option {
+	return optionFunc(func(a *Driver) {
+		a.timeout = d
+	})
+}
+
+// WithBaseDir sets the directory used to resolve relative paths given to
+// functions such as file and templatefile
+func WithBaseDir(dir string) Option {
+	return optionFunc(func(a *Driver) {
+		a.baseDir = dir
+	})
+}
+
+// WithSecrets sets the provider used to resolve the secret function. Values of
+// secrets are masked in the messages logged by tasks and in outputs.
+func WithSecrets(p SecretProvider) Option {
+	return optionFunc(func(a *Driver) {
+		a.secrets = p
+	})
+}
+
 func WithProtocol(p Protocol) Option {
 	return optionFunc(func(a *Driver) {
 		a.protocol = p
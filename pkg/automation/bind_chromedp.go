@@ -10,13 +10,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/model"
 	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/device"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 type produceQueryActionFunc = func(any, ...chromedp.QueryOption) chromedp.QueryAction
@@ -79,7 +82,13 @@ func bindTask(task model.Task) chromedp.Action {
 			), nil
 		})
 	case *model.Sleep:
-		return tasks(chromedp.Sleep(t.Duration), printf("Sleep %v", t.Duration))
+		return taskThunk(func(c context.Context) (Task, error) {
+			d, err := evalDuration(c, t.Duration)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sleep duration: %w", err)
+			}
+			return tasks(chromedp.Sleep(d), printf("Sleep %v", d)), nil
+		})
 	case *model.Reload:
 		return tasks(chromedp.Reload(), printf("Reload"))
 	case *model.Stop:
@@ -109,7 +118,11 @@ func bindTask(task model.Task) chromedp.Action {
 	case *model.Eval:
 		return usingVariable(t.Name, func(msg *json.RawMessage) chromedp.Action {
 			return taskThunk(func(c context.Context) (Task, error) {
-				return tasks(chromedp.Evaluate(t.Script, msg), printf("Evaluate script `%s'", t.Name)), nil
+				script, err := evalString(c, t.Script)
+				if err != nil {
+					return nil, err
+				}
+				return tasks(chromedp.Evaluate(script, msg), printf("Evaluate script `%s'", t.Name)), nil
 			})
 		})
 
@@ -121,26 +134,92 @@ func bindTask(task model.Task) chromedp.Action {
 		})
 
 	case *model.InnerHTML:
-		return usingStringVariable(t.Name, func(str *string) chromedp.Action {
-			return taskThunk(func(c context.Context) (Task, error) {
-				return tasks(
-					printSelector(fmt.Sprintf("Extract inner HTML into variable `%s'", t.Name), t.Selectors, t.Options),
-					bindSelector(
-						func(sel any, opts ...chromedp.QueryOption) chromedp.QueryAction {
-							return chromedp.InnerHTML(sel, str, opts...)
-						}, t.Selectors, t.Options),
-				), nil
-			})
+		return bindStringCapture("inner HTML", t.Name, chromedp.InnerHTML, t.Selectors, t.Options)
+
+	case *model.OuterHTML:
+		return bindStringCapture("outer HTML", t.Name, chromedp.OuterHTML, t.Selectors, t.Options)
+
+	case *model.Text:
+		return bindStringCapture("text", t.Name, chromedp.Text, t.Selectors, t.Options)
+
+	case *model.Value:
+		return bindStringCapture("value", t.Name, chromedp.Value, t.Selectors, t.Options)
+
+	case *model.Attribute:
+		return bindAttributeCapture(t)
+
+	case *model.Count:
+		return usingVariable(t.Name, func(msg *json.RawMessage) chromedp.Action {
+			return tasks(
+				printSelector(fmt.Sprintf("Count elements into variable `%s'", t.Name), t.Selectors, t.Options),
+				TaskFunc(func(c context.Context) error {
+					items, err := queryNodeItems(t.Selectors, t.Options)(c)
+					if err != nil {
+						return err
+					}
+					*msg, err = json.Marshal(len(items))
+					return err
+				}),
+			)
 		})
 
+	case *model.Extract:
+		return bindExtract(t)
+
 	case *model.Version:
 		return printBrowserVersion(browser.GetVersion())
 
+	case *model.Assert:
+		return bindAssert(t)
+
 	default:
 		panic(fmt.Errorf("unexpected task type %T", t))
 	}
 }
 
+// bindStringCapture produces a task which captures a string from the element
+// matched by the selectors into the named variable
+func bindStringCapture(desc string, name string, fn func(any, *string, ...chromedp.QueryOption) chromedp.QueryAction, sels []*model.Selector, options *model.Options) chromedp.Action {
+	return usingStringVariable(name, func(str *string) chromedp.Action {
+		return tasks(
+			printSelector(fmt.Sprintf("Extract %s into variable `%s'", desc, name), sels, options),
+			bindSelector(func(sel any, opts ...chromedp.QueryOption) chromedp.QueryAction {
+				return fn(sel, str, opts...)
+			}, sels, options),
+		)
+	})
+}
+
+// bindAttributeCapture produces a task which captures an attribute of the
+// element matched by the selectors into the named variable
+func bindAttributeCapture(t *model.Attribute) chromedp.Action {
+	return usingValue(t.Name, func(v *cty.Value) chromedp.Action {
+		var (
+			str string
+			ok  bool
+		)
+		return tasks(
+			printSelector(fmt.Sprintf("Extract attribute `%s' into variable `%s'", t.Attribute, t.Name), t.Selectors, t.Options),
+			bindSelector(func(sel any, opts ...chromedp.QueryOption) chromedp.QueryAction {
+				return chromedp.AttributeValue(sel, t.Attribute, &str, &ok, opts...)
+			}, t.Selectors, t.Options),
+			TaskFunc(func(context.Context) error {
+				*v = attributeValue(str, ok)
+				return nil
+			}),
+		)
+	})
+}
+
+// attributeValue converts the value of an attribute, which is null when the
+// element does not have the attribute
+func attributeValue(str string, found bool) cty.Value {
+	if !found {
+		return cty.NullVal(cty.String)
+	}
+	return cty.StringVal(str)
+}
+
 func printBrowserVersion(params *browser.GetVersionParams) TaskFunc {
 	return func(ctx context.Context) error {
 		protocolVersion, product, revision, userAgent, jsVersion, err := params.Do(ctx)
@@ -154,39 +233,222 @@ func printBrowserVersion(params *browser.GetVersionParams) TaskFunc {
 }
 
 func umarshalData(msg json.RawMessage) cty.Value {
-	// TODO Additional types might occur
-	m := map[string]string{}
-	_ = json.Unmarshal(msg, &m)
-	v, _ := gocty.ToCtyValue(m, cty.Map(cty.String))
+	ty, err := ctyjson.ImpliedType(msg)
+	if err != nil {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	v, err := ctyjson.Unmarshal(msg, ty)
+	if err != nil {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
 	return v
 }
 
 func bindSelector(fn produceQueryActionFunc, sels []*model.Selector, options *model.Options) Task {
-	var tasks chromedp.Tasks = make([]chromedp.Action, len(sels))
+	return taskThunk(func(c context.Context) (Task, error) {
+		targets, err := evalTargets(c, sels)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := evalQueryOptions(c, options)
+		if err != nil {
+			return nil, err
+		}
+
+		quoted := make([]string, len(targets))
+		for i, t := range targets {
+			quoted[i] = fmt.Sprintf("`%s'", t)
+		}
+		desc := "query for " + cmp.Or(strings.Join(quoted, ","), "current element")
+
+		return timeoutTask(opts.timeout, desc, taskThunk(func(c context.Context) (Task, error) {
+			// Within a for_each over elements, a task without selectors targets
+			// the current element and selectors query within it
+			node := scopeNodeFrom(c)
+			if node != nil && len(sels) == 0 {
+				queryOpts := append([]chromedp.QueryOption{chromedp.ByNodeID}, bindQueryOptions(opts)...)
+				return fn([]cdp.NodeID{node.NodeID}, queryOpts...), nil
+			}
+
+			var tasks chromedp.Tasks = make([]chromedp.Action, len(sels))
+			for i, s := range sels {
+				queryOpts := make([]chromedp.QueryOption, 0)
+				if s.By != "" {
+					queryOpts = append(queryOpts, bindSelectorBy(s.By))
+				}
+				if s.On != "" {
+					queryOpts = append(queryOpts, bindSelectorOn(s.On))
+				}
+				if node != nil {
+					queryOpts = append(queryOpts, chromedp.FromNode(node))
+				}
+				queryOpts = append(queryOpts, bindQueryOptions(opts)...)
+				tasks[i] = fn(targets[i], queryOpts...)
+			}
+			return tasks, nil
+		})), nil
+	})
+}
+
+// queryOptions contains the values of the options of a selector query
+type queryOptions struct {
+	retryInterval *time.Duration
+	atLeast       *int
+	timeout       time.Duration
+}
+
+func evalQueryOptions(c context.Context, o *model.Options) (queryOptions, error) {
+	res := queryOptions{timeout: model.DefaultQueryTimeout}
+	if o == nil {
+		return res, nil
+	}
+	if o.RetryInterval != nil {
+		d, err := evalDuration(c, o.RetryInterval)
+		if err != nil {
+			return res, fmt.Errorf("invalid retry_interval: %w", err)
+		}
+		res.retryInterval = &d
+	}
+	if o.AtLeast != nil {
+		n, err := evalInt(c, o.AtLeast)
+		if err != nil {
+			return res, fmt.Errorf("invalid at_least: %w", err)
+		}
+		res.atLeast = &n
+	}
+	if o.Timeout != nil {
+		d, err := evalDuration(c, o.Timeout)
+		if err != nil {
+			return res, fmt.Errorf("invalid timeout: %w", err)
+		}
+		res.timeout = d
+	}
+	return res, nil
+}
+
+func evalTargets(c context.Context, sels []*model.Selector) ([]string, error) {
+	targets := make([]string, len(sels))
 	for i, s := range sels {
-		opts := make([]chromedp.QueryOption, 0)
-		if s.By != "" {
-			opts = append(opts, bindSelectorBy(s.By))
+		target, err := evalString(c, s.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector target: %w", err)
 		}
-		if s.On != "" {
-			opts = append(opts, bindSelectorOn(s.On))
+		targets[i] = target
+	}
+	return targets, nil
+}
+
+// queryNodeItems queries the elements matched by the selectors to produce
+// the items of a for_each loop. Each element becomes the scope of the tasks
+// run for its iteration.
+func queryNodeItems(sels []*model.Selector, options *model.Options) func(context.Context) ([]iteration, error) {
+	query := queryNodes(sels, options)
+
+	return func(c context.Context) ([]iteration, error) {
+		nodes, err := query(c)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]iteration, len(nodes))
+		for i, node := range nodes {
+			res[i] = iteration{
+				key:   cty.NumberIntVal(int64(i)),
+				value: nodeValue(node),
+				scope: func(c context.Context) context.Context {
+					return withScopeNode(c, node)
+				},
+			}
 		}
-		opts = append(opts, bindQueryOptions(options)...)
-		tasks[i] = fn(s.Target, opts...)
+		return res, nil
 	}
-	return tasks
 }
 
-func printSelector(desc string, sels []*model.Selector, options *model.Options) Task {
-	selectorDesc := make([]string, len(sels))
-	var opts string
+// queryNodes queries the elements matched by the selectors
+func queryNodes(sels []*model.Selector, options *model.Options) func(context.Context) ([]*cdp.Node, error) {
+	// Unless specified, allow no elements to match rather than waiting
+	// indefinitely for one to appear
+	var opts model.Options
 	if options != nil {
-		opts = fmt.Sprintf(" at %v/%v", options.AtLeast, options.RetryInterval)
+		opts = *options
 	}
-	for i, s := range sels {
-		selectorDesc[i] = fmt.Sprintf("%s (%s, on=%s%v)", s.Target, s.By, s.On, opts)
+	if opts.AtLeast == nil {
+		opts.AtLeast = model.Literal(cty.Zero)
+	}
+
+	return func(c context.Context) ([]*cdp.Node, error) {
+		var nodes []*cdp.Node
+		err := bindSelector(func(sel any, queryOpts ...chromedp.QueryOption) chromedp.QueryAction {
+			return chromedp.ActionFunc(func(c context.Context) error {
+				var found []*cdp.Node
+				if err := chromedp.Nodes(sel, &found, queryOpts...).Do(c); err != nil {
+					return err
+				}
+				nodes = append(nodes, found...)
+				return nil
+			})
+		}, sels, &opts).Do(c)
+		if err != nil {
+			return nil, err
+		}
+		return nodes, nil
+	}
+}
+
+func nodeValue(node *cdp.Node) cty.Value {
+	attrs := map[string]cty.Value{}
+	for i := 0; i+1 < len(node.Attributes); i += 2 {
+		attrs[node.Attributes[i]] = cty.StringVal(node.Attributes[i+1])
+	}
+	attributes := cty.MapValEmpty(cty.String)
+	if len(attrs) > 0 {
+		attributes = cty.MapVal(attrs)
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"node_name":  cty.StringVal(strings.ToLower(node.NodeName)),
+		"attributes": attributes,
+	})
+}
+
+func scopeNodeFrom(c context.Context) *cdp.Node {
+	node, _ := c.Value(scopeNodeKey).(*cdp.Node)
+	return node
+}
+
+func withScopeNode(c context.Context, node *cdp.Node) context.Context {
+	return context.WithValue(c, scopeNodeKey, node)
+}
+
+func printSelector(desc string, sels []*model.Selector, options *model.Options) Task {
+	return taskThunk(func(c context.Context) (Task, error) {
+		targets, err := evalTargets(c, sels)
+		if err != nil {
+			return nil, err
+		}
+
+		var opts string
+		if options != nil {
+			o, err := evalQueryOptions(c, options)
+			if err != nil {
+				return nil, err
+			}
+			opts = fmt.Sprintf(" at %s/%s", optionalString(o.atLeast), optionalString(o.retryInterval))
+		}
+
+		selectorDesc := make([]string, len(sels))
+		for i, s := range sels {
+			selectorDesc[i] = fmt.Sprintf("%s (%s, on=%s%v)", targets[i], s.By, s.On, opts)
+		}
+		return printf("%s %s", desc, strings.Join(selectorDesc, ",")), nil
+	})
+}
+
+func optionalString[T any](v *T) string {
+	if v == nil {
+		return "default"
 	}
-	return printf("%s %s", desc, strings.Join(selectorDesc, ","))
+	return fmt.Sprint(*v)
 }
 
 func bindSelectorBy(s model.SelectorBy) chromedp.QueryOption {
@@ -223,15 +485,12 @@ func bindSelectorOn(s model.SelectorOn) chromedp.QueryOption {
 	return nil
 }
 
-func bindQueryOptions(opts *model.Options) (results []chromedp.QueryOption) {
-	if opts == nil {
-		return
+func bindQueryOptions(opts queryOptions) (results []chromedp.QueryOption) {
+	if opts.retryInterval != nil {
+		results = append(results, chromedp.RetryInterval(*opts.retryInterval))
 	}
-	if opts.RetryInterval != nil {
-		results = append(results, chromedp.RetryInterval(*opts.RetryInterval))
-	}
-	if opts.AtLeast != nil {
-		results = append(results, chromedp.AtLeast(*opts.AtLeast))
+	if opts.atLeast != nil {
+		results = append(results, chromedp.AtLeast(*opts.atLeast))
 	}
 	return
 }
@@ -297,14 +556,31 @@ func usingStringVariable(name string, fn usingStringVariableFunc) chromedp.Actio
 	})
 }
 
+// usingValue captures the value which fn produces into the named variable,
+// keeping its type, and into the outputs as JSON
+func usingValue(name string, fn func(*cty.Value) chromedp.Action) chromedp.Action {
+	return chromedp.ActionFunc(func(c context.Context) error {
+		res := mustAutomationResult(c)
+		v := cty.NullVal(cty.DynamicPseudoType)
+
+		err := fn(&v).Do(c)
+
+		msg, _ := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+		raw := json.RawMessage(msg)
+		res.Outputs[name] = &raw
+		evalContextFrom(c).Variables[name] = v
+
+		return err
+	})
+}
+
 func tasks(t ...Task) Tasks {
 	return Tasks(t)
 }
 
 func printf(format string, args ...any) TaskFunc {
 	return func(c context.Context) error {
-		fmt.Printf(format, args...)
-		fmt.Println()
+		fmt.Println(secretMaskFrom(c).apply(fmt.Sprintf(format, args...)))
 		return nil
 	}
 }
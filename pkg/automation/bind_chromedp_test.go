@@ -42,21 +42,33 @@ var _ = Describe("Bind", func() {
 				Expect(output.Tasks).To(HaveLen(1))
 				Expect(output.Tasks[0]).NotTo(BeNil())
 			},
+			Entry("assert", new(model.Assert)),
+			Entry("attribute", new(model.Attribute)),
 			Entry("click", new(model.Click)),
 			Entry("double_click", new(model.DoubleClick)),
 			Entry("blur", new(model.Blur)),
 			Entry("clear", new(model.Clear)),
+			Entry("count", new(model.Count)),
 			Entry("eval", new(model.Eval)),
+			Entry("extract", new(model.Extract)),
+			Entry("flow", new(model.Flow)),
+			Entry("for_each", new(model.ForEach)),
+			Entry("if", new(model.If)),
 			Entry("inner_html", new(model.InnerHTML)),
 			Entry("navigate", new(model.Navigate)),
 			Entry("navigate_back", new(model.NavigateBack)),
 			Entry("navigate_forward", new(model.NavigateForward)),
+			Entry("outer_html", new(model.OuterHTML)),
 			Entry("reload", new(model.Reload)),
+			Entry("retry", new(model.Retry)),
 			Entry("screenshot", new(model.Screenshot)),
 			Entry("send_keys", new(model.SendKeys)),
 			Entry("sleep", new(model.Sleep)),
 			Entry("stop", new(model.Stop)),
+			Entry("text", new(model.Text)),
 			Entry("title", new(model.Title)),
+			Entry("try", new(model.Try)),
+			Entry("value", new(model.Value)),
 			Entry("wait_visible", new(model.WaitVisible)),
 		)
 	})
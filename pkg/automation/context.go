@@ -5,10 +5,16 @@ package automation
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/model"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 type contextKey string
@@ -16,6 +22,12 @@ type contextKey string
 const (
 	evalContextKey      contextKey = "evalContext"
 	automationResultKey contextKey = "automationResult"
+	scopeNodeKey        contextKey = "scopeNode"
+	globalsKey          contextKey = "globals"
+	flowStackKey        contextKey = "flowStack"
+	packageKey          contextKey = "package"
+	functionsKey        contextKey = "functions"
+	secretMaskKey       contextKey = "secretMask"
 )
 
 func evalContext(c context.Context, expr model.Expression) (cty.Value, error) {
@@ -24,11 +36,61 @@ func evalContext(c context.Context, expr model.Expression) (cty.Value, error) {
 	}
 	ec := evalContextFrom(c)
 	return expr.Value(&model.Scope{
-		Variables: ec.Variables,
+		Variables: scopeVariables(ec),
 		Functions: ec.Functions,
 	})
 }
 
+func evalString(c context.Context, expr model.Expression) (string, error) {
+	v, err := evalContext(c, expr)
+	if err != nil {
+		return "", err
+	}
+	v, err = convert.Convert(v, cty.String)
+	if err != nil {
+		return "", err
+	}
+	if v.IsNull() {
+		return "", fmt.Errorf("value must not be null")
+	}
+	return v.AsString(), nil
+}
+
+func evalInt(c context.Context, expr model.Expression) (int, error) {
+	v, err := evalContext(c, expr)
+	if err != nil {
+		return 0, err
+	}
+	var res int
+	err = gocty.FromCtyValue(v, &res)
+	return res, err
+}
+
+// evalDuration evaluates an expression which provides a duration as a string
+// such as "5s". A nil expression produces zero.
+func evalDuration(c context.Context, expr model.Expression) (time.Duration, error) {
+	if expr == nil {
+		return 0, nil
+	}
+	text, err := evalString(c, expr)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(text)
+}
+
+// scopeVariables flattens the variables of the evaluation context and its
+// parents, where variables in child contexts hide those of the parents.
+func scopeVariables(ec *hcl.EvalContext) map[string]cty.Value {
+	if ec.Parent() == nil {
+		return ec.Variables
+	}
+	res := map[string]cty.Value{}
+	maps.Copy(res, scopeVariables(ec.Parent()))
+	maps.Copy(res, ec.Variables)
+	return res
+}
+
 func evalContextFrom(c context.Context) *hcl.EvalContext {
 	ec := c.Value(evalContextKey).(*hcl.EvalContext)
 	if ec.Variables == nil {
@@ -46,6 +108,50 @@ func withAutomationResult(c context.Context, ar *Result) context.Context {
 	return context.WithValue(c, automationResultKey, ar)
 }
 
-func withEvalContext(c context.Context) context.Context {
-	return context.WithValue(c, evalContextKey, &hcl.EvalContext{})
+// withScope derives a context with a child evaluation context that contains
+// the given variables. Variables captured within the scope are stored in the
+// child evaluation context.
+func withScope(c context.Context, vars map[string]cty.Value) context.Context {
+	ec := evalContextFrom(c).NewChild()
+	ec.Variables = maps.Clone(vars)
+	ec.Functions = ec.Parent().Functions
+	return context.WithValue(c, evalContextKey, ec)
+}
+
+// withEvalContext derives a context with a new evaluation context that
+// contains only the global variables and the functions
+func withEvalContext(c context.Context, globals map[string]cty.Value) context.Context {
+	ec := &hcl.EvalContext{
+		Variables: maps.Clone(globals),
+		Functions: functionsFrom(c),
+	}
+	c = context.WithValue(c, globalsKey, globals)
+	return context.WithValue(c, evalContextKey, ec)
+}
+
+// withFunctions derives a context which provides the functions that can be
+// called from expressions
+func withFunctions(c context.Context, funcs map[string]function.Function) context.Context {
+	return context.WithValue(c, functionsKey, funcs)
+}
+
+func functionsFrom(c context.Context) map[string]function.Function {
+	funcs, _ := c.Value(functionsKey).(map[string]function.Function)
+	return funcs
+}
+
+func globalsFrom(c context.Context) map[string]cty.Value {
+	globals, _ := c.Value(globalsKey).(map[string]cty.Value)
+	return globals
+}
+
+// withPackage derives a context for running the automations of the package,
+// which is empty for the workspace. Flows resolve names within it.
+func withPackage(c context.Context, pkg string) context.Context {
+	return context.WithValue(c, packageKey, pkg)
+}
+
+func packageFrom(c context.Context) string {
+	pkg, _ := c.Value(packageKey).(string)
+	return pkg
 }
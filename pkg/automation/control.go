@@ -0,0 +1,209 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ifTask runs then when the condition is true, otherwise it runs otherwise
+func ifTask(cond model.Expression, then, otherwise Task) Task {
+	return taskThunk(func(c context.Context) (Task, error) {
+		ok, err := evalCondition(c, cond)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return then, nil
+		}
+		return tasks(printf("Skip tasks (condition not met)"), otherwise), nil
+	})
+}
+
+func evalCondition(c context.Context, cond model.Expression) (bool, error) {
+	v, err := evalContext(c, cond)
+	if err != nil {
+		return false, err
+	}
+	if v.IsNull() {
+		return false, fmt.Errorf("invalid condition: value must not be null")
+	}
+
+	v, err = convert.Convert(v, cty.Bool)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition: %w", err)
+	}
+	return v.True(), nil
+}
+
+// iteration is an item of a for_each loop
+type iteration struct {
+	key   cty.Value
+	value cty.Value
+
+	// scope, when set, derives the context used to run the tasks for the
+	// iteration
+	scope func(context.Context) context.Context
+}
+
+// forEachTask runs body once for each of the items. The outputs captured by
+// each iteration are collected into a list stored under the name.
+func forEachTask(name string, items func(context.Context) ([]iteration, error), body Task) Task {
+	return TaskFunc(func(c context.Context) error {
+		its, err := items(c)
+		if err != nil {
+			return err
+		}
+
+		res := mustAutomationResult(c)
+		outputs := make([]map[string]*json.RawMessage, 0, len(its))
+		values := make([]cty.Value, 0, len(its))
+
+		for i, it := range its {
+			_ = printf("For each (%d of %d)", i+1, len(its)).Do(c)
+
+			iterResult := newResult()
+			ic := withScope(withAutomationResult(c, iterResult), map[string]cty.Value{
+				"each": cty.ObjectVal(map[string]cty.Value{
+					"key":   it.key,
+					"value": it.value,
+				}),
+			})
+			if it.scope != nil {
+				ic = it.scope(ic)
+			}
+
+			err = body.Do(ic)
+
+			maps.Copy(res.OutputFiles, iterResult.OutputFiles)
+			res.Errors = append(res.Errors, iterResult.Errors...)
+			for location, n := range iterResult.Attempts {
+				res.Attempts[location] = append(res.Attempts[location], n...)
+			}
+			outputs = append(outputs, iterResult.Outputs)
+
+			captured := maps.Clone(evalContextFrom(ic).Variables)
+			delete(captured, "each")
+			values = append(values, cty.ObjectVal(captured))
+
+			if err != nil {
+				break
+			}
+		}
+
+		if name != "" {
+			msg, _ := json.Marshal(outputs)
+			raw := json.RawMessage(msg)
+			res.Outputs[name] = &raw
+			evalContextFrom(c).Variables[name] = cty.TupleVal(values)
+		}
+		return err
+	})
+}
+
+// evalItems evaluates the expression to produce the items of a for_each loop
+func evalItems(expr model.Expression) func(context.Context) ([]iteration, error) {
+	return func(c context.Context) ([]iteration, error) {
+		v, err := evalContext(c, expr)
+		if err != nil {
+			return nil, err
+		}
+		if v.IsNull() || !v.IsWhollyKnown() || !v.CanIterateElements() {
+			return nil, fmt.Errorf("invalid for_each items: value must be a list, set, map, or object")
+		}
+
+		res := make([]iteration, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			k, e := it.Element()
+			res = append(res, iteration{key: k, value: e})
+		}
+		return res, nil
+	}
+}
+
+// tryTask runs body and when it fails, records the error in the result and
+// runs catch with the error in scope. finally runs regardless of the outcome.
+func tryTask(body, catch, finally Task) Task {
+	return TaskFunc(func(c context.Context) error {
+		err := body.Do(c)
+		if err != nil {
+			res := mustAutomationResult(c)
+			res.Errors = append(res.Errors, err.Error())
+
+			_ = printf("Caught error: %v", err).Do(c)
+			cc := withScope(c, map[string]cty.Value{
+				"error": cty.ObjectVal(map[string]cty.Value{
+					"message": cty.StringVal(err.Error()),
+				}),
+			})
+			err = catch.Do(cc)
+
+			// Values captured by the catch tasks remain in scope after the try
+			captured := evalContextFrom(cc).Variables
+			delete(captured, "error")
+			maps.Copy(evalContextFrom(c).Variables, captured)
+		}
+
+		return errors.Join(err, finally.Do(c))
+	})
+}
+
+// retryTask runs body until it succeeds or the attempts are exhausted. The
+// delay between attempts starts at backoff and is scaled by multiplier. The
+// number of attempts is recorded in the result under the location.
+func retryTask(location string, attempts int, backoff time.Duration, multiplier float64, body Task) Task {
+	return TaskFunc(func(c context.Context) error {
+		var (
+			err   error
+			delay = backoff
+			n     int
+		)
+		for n = 1; n <= attempts; n++ {
+			_ = printf("Attempt %d of %d", n, attempts).Do(c)
+			if err = body.Do(c); err == nil || n == attempts {
+				break
+			}
+
+			_ = printf("Attempt %d failed: %v", n, err).Do(c)
+			select {
+			case <-c.Done():
+				err = c.Err()
+			case <-time.After(delay):
+			}
+			if c.Err() != nil {
+				break
+			}
+			delay = time.Duration(float64(delay) * multiplier)
+		}
+
+		res := mustAutomationResult(c)
+		res.Attempts[location] = append(res.Attempts[location], min(n, attempts))
+		return err
+	})
+}
+
+// timeoutTask runs body with a deadline. When the deadline elapses, the error
+// names the task using its description.
+func timeoutTask(d time.Duration, desc string, body Task) Task {
+	return TaskFunc(func(c context.Context) error {
+		tc, cancel := context.WithTimeout(c, d)
+		defer cancel()
+
+		err := body.Do(tc)
+		if err != nil && c.Err() == nil && errors.Is(tc.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s timed out after %v", desc, d)
+		}
+		return err
+	})
+}
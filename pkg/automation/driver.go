@@ -5,13 +5,17 @@
 package automation
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/config"
 	"github.com/Carbonfrost/autogun/pkg/model"
 	"github.com/chromedp/chromedp"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 )
 
 type Driver struct {
@@ -19,27 +23,45 @@ type Driver struct {
 	allocator   *Allocator
 	protocol    Protocol
 	model       *model.Model
+	variables   map[string]cty.Value
+	timeout     time.Duration
+	baseDir     string
+	secrets     SecretProvider
 }
 
-// Automation get the automation by name
+// Automation get the automation by name, which is qualified by its package
+// for automations of imported packages
 func (d *Driver) Automation(name string) *Automation {
 	return d.automations[name]
 }
 
 // Execute will execute the named automation
 func (d *Driver) Execute(ctx context.Context, auto *model.Automation) (*Result, error) {
+	mask := &secretMask{}
+	globals, err := d.resolveGlobals(mask)
+	if err != nil {
+		return nil, err
+	}
+
+	browser, err := d.model.ResolveBrowser(d.allocator.Profile)
+	if err != nil {
+		return nil, err
+	}
+	allocator := d.allocator.withBrowser(browser)
+
 	res := newResult()
-	ctx, cancel, err := d.allocator.newContext(
-		withAutomationResult(ctx, res),
-	)
+	ctx = withFunctions(withAutomationResult(ctx, res), d.functions(mask))
+	ctx = withSecretMask(ctx, mask)
+	ctx = withPackage(ctx, auto.Package)
+	ctx, cancel, err := allocator.newContext(withEvalContext(ctx, globals))
 	if err != nil {
 		return nil, err
 	}
 	defer cancel()
 
 	var emulate Task = TaskFunc(nil)
-	if dev, ok := d.allocator.resolveDevice(); ok {
-		fmt.Fprintf(os.Stderr, "Emulating device %s (%s)\n", dev.Name, d.allocator.DeviceID)
+	if dev, ok := allocator.resolveDevice(d.model); ok {
+		fmt.Fprintf(os.Stderr, "Emulating device %s (%s)\n", dev.Name, dev.ID)
 		emulate = chromedp.Emulate(bindDevice(dev))
 	}
 
@@ -48,40 +70,197 @@ func (d *Driver) Execute(ctx context.Context, auto *model.Automation) (*Result,
 		return nil, err
 	}
 
-	return res, chromedp.Run(ctx, emulate, a)
+	err = chromedp.Run(ctx, emulate, d.runTask(a, auto.Outputs))
+	mask.applyOutputs(res.Outputs)
+	for i, e := range res.Errors {
+		res.Errors[i] = mask.apply(e)
+	}
+	return res, err
 }
 
-func (d *Driver) buildAutomation(m *model.Automation) (*Automation, error) {
-	actions := make([]Task, 0)
-	for _, t := range m.Tasks {
-		switch task := t.(type) {
-		case *model.Flow:
-			actions = append(actions, d.flow(task.Name))
-		case *model.Source:
-			actions = append(actions, d.runSource(task.Filename))
-		default:
-			tsk, err := d.protocol.BindTask(t)
-			if err != nil {
-				return nil, err
-			}
-			actions = append(actions, tsk)
+// runTask produces the task which runs the automation and then evaluates its
+// outputs, both within the timeout of the run
+func (d *Driver) runTask(a Task, outputs []*model.Output) Task {
+	var run Task = tasks(a, outputsTask(outputs))
+	if d.timeout > 0 {
+		run = timeoutTask(d.timeout, "run", run)
+	}
+	return run
+}
+
+// resolveGlobals determines the values of the var and local namespaces which
+// are in scope for every expression.
+func (d *Driver) resolveGlobals(mask *secretMask) (map[string]cty.Value, error) {
+	vars, err := d.resolveVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	locals, err := d.resolveLocals(cty.ObjectVal(vars), mask)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]cty.Value{
+		"var":   cty.ObjectVal(vars),
+		"local": cty.ObjectVal(locals),
+	}, nil
+}
+
+// resolveLocals evaluates each local after the locals it depends upon.
+func (d *Driver) resolveLocals(vars cty.Value, mask *secretMask) (map[string]cty.Value, error) {
+	sorted, err := model.SortLocals(d.model.Locals)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]cty.Value{}
+	for _, l := range sorted {
+		value, err := l.Value.Value(&model.Scope{
+			Variables: map[string]cty.Value{
+				"var":   vars,
+				"local": cty.ObjectVal(res),
+			},
+			Functions: d.functions(mask),
+		})
+		if err != nil {
+			return nil, err
+		}
+		res[l.Name] = value
+	}
+	return res, nil
+}
+
+// functions gets the functions which can be called from expressions. Files are
+// read relative to the base directory of the driver, and secrets revealed by
+// the secret function are recorded in the mask.
+func (d *Driver) functions(mask *secretMask) map[string]function.Function {
+	funcs := model.Functions(cmp.Or(d.baseDir, "."))
+	funcs["secret"] = secretFunc(d.secrets, mask)
+	return funcs
+}
+
+// Functions gets the functions which can be called from expressions within
+// automations, including the secret function which uses the given provider
+func Functions(baseDir string, secrets SecretProvider) map[string]function.Function {
+	funcs := model.Functions(baseDir)
+	funcs["secret"] = secretFunc(secrets, nil)
+	return funcs
+}
+
+// resolveVariables determines the value of each variable declared in the
+// model, preferring the values provided to the driver over defaults.
+func (d *Driver) resolveVariables() (map[string]cty.Value, error) {
+	res := map[string]cty.Value{}
+	for name := range d.variables {
+		if d.model.Variable(name) == nil {
+			warnf("value provided for undeclared variable %q", name)
 		}
 	}
 
+	for _, v := range d.model.Variables {
+		var (
+			value cty.Value
+			err   error
+		)
+		if given, ok := d.variables[v.Name]; ok {
+			value, err = v.Convert(given)
+		} else {
+			value, err = v.DefaultValue()
+		}
+		if err != nil {
+			return nil, err
+		}
+		res[v.Name] = value
+	}
+	return res, nil
+}
+
+func (d *Driver) buildAutomation(m *model.Automation) (*Automation, error) {
+	actions, err := d.buildTasks(m.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Timeout > 0 {
+		desc := fmt.Sprintf("automation %q (%s)", m.QualifiedName(), m.Location)
+		actions = Tasks{timeoutTask(m.Timeout, desc, actions)}
+	}
+
 	return &Automation{
 		Name:  m.Name,
 		Tasks: actions,
 	}, nil
 }
 
-func (d *Driver) flow(name string) Task {
-	return taskThunk(func(c context.Context) (Task, error) {
-		a := d.Automation(name)
-		if a == nil {
-			return nil, fmt.Errorf("automation not found %q", name)
+func (d *Driver) buildTasks(tasks []model.Task) (Tasks, error) {
+	actions := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		tsk, err := d.buildTask(t)
+		if err != nil {
+			return nil, err
 		}
-		return a, nil
-	})
+		actions = append(actions, tsk)
+	}
+	return actions, nil
+}
+
+func (d *Driver) buildTask(t model.Task) (Task, error) {
+	switch task := t.(type) {
+	case *model.Flow:
+		return d.flow(task.Name, task.Args), nil
+	case *model.Source:
+		return d.runSource(task.Filename), nil
+	case *model.If:
+		then, err := d.buildTasks(task.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		otherwise, err := d.buildTasks(task.Else)
+		if err != nil {
+			return nil, err
+		}
+		return ifTask(task.Condition, then, otherwise), nil
+	case *model.ForEach:
+		body, err := d.buildTasks(task.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		items := evalItems(task.Items)
+		if task.Items == nil {
+			items = queryNodeItems(task.Selectors, task.Options)
+		}
+		return forEachTask(task.Name, items, body), nil
+	case *model.Retry:
+		body, err := d.buildTasks(task.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		return retryTask(task.Location, task.Attempts, task.Backoff, task.Multiplier, body), nil
+	case *model.Timeout:
+		body, err := d.buildTask(task.Task)
+		if err != nil {
+			return nil, err
+		}
+		desc := fmt.Sprintf("%s task (%s)", task.BlockType, task.Location)
+		return timeoutTask(task.Duration, desc, body), nil
+	case *model.Try:
+		body, err := d.buildTasks(task.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		catch, err := d.buildTasks(task.Catch)
+		if err != nil {
+			return nil, err
+		}
+		finally, err := d.buildTasks(task.Finally)
+		if err != nil {
+			return nil, err
+		}
+		return tryTask(body, catch, finally), nil
+	default:
+		return d.protocol.BindTask(t)
+	}
 }
 
 func (d *Driver) runSource(source string) Task {
@@ -93,6 +272,10 @@ func (d *Driver) runSource(source string) Task {
 			return nil, diag
 		}
 
+		if diag := config.ResolveSelectors(file); diag.HasErrors() {
+			return nil, diag
+		}
+
 		// TODO Assumes one automation per file which may not be true
 		return d.buildAutomation(model.FromConfig(file.Automations[0]))
 	})
@@ -0,0 +1,226 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// nextPageInterval is the time between checks for the root elements of the
+// next page
+const nextPageInterval = 100 * time.Millisecond
+
+// bindExtract produces a task that captures a list of objects, one for each
+// element matched by the root selectors on each page
+func bindExtract(t *model.Extract) chromedp.Action {
+	var next func(context.Context, []*cdp.Node) (bool, error)
+	if len(t.Next) > 0 {
+		next = nextPage(t)
+	}
+
+	return usingValue(t.Name, func(v *cty.Value) chromedp.Action {
+		return tasks(
+			printSelector(fmt.Sprintf("Extract list into variable `%s'", t.Name), t.Selectors, t.Options),
+			TaskFunc(func(c context.Context) error {
+				rows, err := extractPages(c, t.MaxPages, extractPage(t), next)
+				if err != nil {
+					return err
+				}
+				*v = extractList(t.Fields, rows)
+				return nil
+			}),
+		)
+	})
+}
+
+// extractPages extracts the rows of each page. Unless the page has no rows or
+// the maximum number of pages is reached, next is called with the root
+// elements of the page to load the following page, which reports false when
+// there is none.
+func extractPages(
+	c context.Context,
+	maxPages int,
+	page func(context.Context) ([]cty.Value, []*cdp.Node, error),
+	next func(context.Context, []*cdp.Node) (bool, error),
+) ([]cty.Value, error) {
+	var rows []cty.Value
+	for n := 1; ; n++ {
+		pageRows, roots, err := page(c)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, pageRows...)
+		if next == nil || len(roots) == 0 || n >= maxPages {
+			return rows, nil
+		}
+
+		ok, err := next(c, roots)
+		if err != nil {
+			return nil, fmt.Errorf("loading page %d: %w", n+1, err)
+		}
+		if !ok {
+			return rows, nil
+		}
+		_ = printf("Extract page %d", n+1).Do(c)
+	}
+}
+
+// extractPage produces the rows of the current page and the root elements
+// they were extracted from
+func extractPage(t *model.Extract) func(context.Context) ([]cty.Value, []*cdp.Node, error) {
+	roots := queryNodes(t.Selectors, t.Options)
+
+	return func(c context.Context) ([]cty.Value, []*cdp.Node, error) {
+		nodes, err := roots(c)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rows := make([]cty.Value, 0, len(nodes))
+		for _, node := range nodes {
+			row, err := extractRow(withScopeNode(c, node), t.Fields)
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, row)
+		}
+		return rows, nodes, nil
+	}
+}
+
+// extractRow reads each of the fields from the first element which matches
+// within the scope node. A field is null when no element matches.
+func extractRow(c context.Context, fields []*model.ExtractField) (cty.Value, error) {
+	attrs := make(map[string]cty.Value, len(fields))
+	for _, f := range fields {
+		nodes, err := queryNodes(f.Selectors, nil)(c)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		var (
+			text  string
+			found bool
+		)
+		if len(nodes) > 0 {
+			node := nodes[0]
+			if f.Attribute != "" {
+				text, found = node.Attribute(f.Attribute)
+			} else {
+				err := chromedp.Text([]cdp.NodeID{node.NodeID}, &text, chromedp.ByNodeID).Do(c)
+				if err != nil {
+					return cty.NilVal, err
+				}
+				found = true
+			}
+		}
+
+		attrs[f.Name], err = fieldValue(f, strings.TrimSpace(text), found)
+		if err != nil {
+			return cty.NilVal, err
+		}
+	}
+	return cty.ObjectVal(attrs), nil
+}
+
+// fieldValue converts the text of a field to its type. Empty text is null
+// unless the field is a string.
+func fieldValue(f *model.ExtractField, text string, found bool) (cty.Value, error) {
+	ty := fieldType(f)
+	if !found || (text == "" && ty != cty.String) {
+		return cty.NullVal(ty), nil
+	}
+
+	v, err := convert.Convert(cty.StringVal(text), ty)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("invalid value of field %q: %w", f.Name, err)
+	}
+	return v, nil
+}
+
+func fieldType(f *model.ExtractField) cty.Type {
+	if f.Type == cty.NilType {
+		return cty.String
+	}
+	return f.Type
+}
+
+// extractList produces the list of rows, which has the type of the fields
+// even when it is empty
+func extractList(fields []*model.ExtractField, rows []cty.Value) cty.Value {
+	if len(rows) == 0 {
+		attrs := make(map[string]cty.Type, len(fields))
+		for _, f := range fields {
+			attrs[f.Name] = fieldType(f)
+		}
+		return cty.ListValEmpty(cty.Object(attrs))
+	}
+	return cty.ListVal(rows)
+}
+
+// nextPage clicks the element matched by the next selectors and waits until
+// the root elements of the previous page are replaced. False is returned when
+// no element matches.
+func nextPage(t *model.Extract) func(context.Context, []*cdp.Node) (bool, error) {
+	roots := queryNodes(t.Selectors, t.Options)
+	nexts := queryNodes(t.Next, nil)
+
+	return func(c context.Context, prev []*cdp.Node) (bool, error) {
+		nodes, err := nexts(c)
+		if err != nil || len(nodes) == 0 {
+			return false, err
+		}
+		opts, err := evalQueryOptions(c, t.Options)
+		if err != nil {
+			return false, err
+		}
+
+		err = chromedp.Click([]cdp.NodeID{nodes[0].NodeID}, chromedp.ByNodeID).Do(c)
+		if err != nil {
+			return false, err
+		}
+		return true, waitReplaced(c, roots, prev, opts.timeout)
+	}
+}
+
+// waitReplaced polls the root elements until one of them is not among the
+// previous ones
+func waitReplaced(c context.Context, roots func(context.Context) ([]*cdp.Node, error), prev []*cdp.Node, timeout time.Duration) error {
+	seen := make(map[cdp.NodeID]bool, len(prev))
+	for _, node := range prev {
+		seen[node.NodeID] = true
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		nodes, err := roots(c)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(nodes, func(node *cdp.Node) bool { return !seen[node.NodeID] }) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for the next page")
+		}
+
+		select {
+		case <-c.Done():
+			return c.Err()
+		case <-time.After(nextPageInterval):
+		}
+	}
+}
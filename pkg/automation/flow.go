@@ -0,0 +1,108 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// flow runs the named automation in its own scope, which contains the global
+// variables and the params of the automation. The name is resolved within the
+// package of the caller. The values that the automation returns are captured
+// in the scope of the caller.
+func (d *Driver) flow(name string, args model.Expression) Task {
+	return TaskFunc(func(c context.Context) error {
+		m, err := d.model.ResolveAutomation(name, packageFrom(c))
+		if err != nil {
+			return err
+		}
+
+		qname := m.QualifiedName()
+		stack := flowStackFrom(c)
+		if slices.Contains(stack, qname) {
+			return fmt.Errorf("recursive flow: %s", strings.Join(append(stack, qname), " -> "))
+		}
+
+		params, err := evalArgs(c, m, args)
+		if err != nil {
+			return err
+		}
+
+		scope := maps.Clone(globalsFrom(c))
+		if scope == nil {
+			scope = map[string]cty.Value{}
+		}
+		scope["param"] = params
+
+		// The automation does not run within the element of a for_each
+		// loop of the caller
+		fc := withScopeNode(withPackage(withEvalContext(c, scope), m.Package), nil)
+		fc = context.WithValue(fc, flowStackKey, append(slices.Clip(stack), qname))
+		if err := d.Automation(qname).Do(fc); err != nil {
+			return err
+		}
+
+		return captureReturns(c, fc, m)
+	})
+}
+
+// evalArgs evaluates the args of a flow, which must provide exactly the params
+// declared by the automation
+func evalArgs(c context.Context, m *model.Automation, args model.Expression) (cty.Value, error) {
+	v, err := evalContext(c, args)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	values := map[string]cty.Value{}
+	if v != cty.NilVal && !v.IsNull() {
+		if !v.Type().IsObjectType() && !v.Type().IsMapType() {
+			return cty.NilVal, fmt.Errorf("flow %q: args must be an object", m.QualifiedName())
+		}
+		values = v.AsValueMap()
+	}
+
+	for name := range values {
+		if !slices.Contains(m.Params, name) {
+			return cty.NilVal, fmt.Errorf("flow %q: unexpected argument %q", m.QualifiedName(), name)
+		}
+	}
+	for _, name := range m.Params {
+		if _, ok := values[name]; !ok {
+			return cty.NilVal, fmt.Errorf("flow %q: missing argument %q", m.QualifiedName(), name)
+		}
+	}
+	return cty.ObjectVal(values), nil
+}
+
+// captureReturns evaluates the returns of the automation in the scope of the
+// flow and stores each of the values in the scope of the caller
+func captureReturns(c, fc context.Context, m *model.Automation) error {
+	v, err := evalContext(fc, m.Returns)
+	if err != nil {
+		return err
+	}
+	if v == cty.NilVal || v.IsNull() {
+		return nil
+	}
+	if !v.Type().IsObjectType() && !v.Type().IsMapType() {
+		return fmt.Errorf("flow %q: returns must be an object", m.QualifiedName())
+	}
+
+	maps.Copy(evalContextFrom(c).Variables, v.AsValueMap())
+	return nil
+}
+
+func flowStackFrom(c context.Context) []string {
+	stack, _ := c.Value(flowStackKey).([]string)
+	return stack
+}
@@ -0,0 +1,702 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation // intentional
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// newTestContext creates the context of running an automation, which has
+// var.show in scope
+func newTestContext() (context.Context, *Result) {
+	res := newResult()
+	return withEvalContext(withAutomationResult(context.Background(), res), map[string]cty.Value{
+		"var": cty.ObjectVal(map[string]cty.Value{
+			"show": cty.True,
+		}),
+	}), res
+}
+
+// expression parses the text of an expression
+func expression(text string) model.Expression {
+	e, diags := hclsyntax.ParseExpression([]byte(text), "-", hcl.InitialPos)
+	Expect(diags).To(BeEmpty())
+	return model.ExpressionFromHCL(e)
+}
+
+// capture produces a task which captures the string value of the expression
+// into the named variable
+func capture(name string, expr string) Task {
+	return TaskFunc(func(c context.Context) error {
+		v, err := evalContext(c, expression(expr))
+		if err != nil {
+			return err
+		}
+		evalContextFrom(c).Variables[name] = v
+		msg, _ := json.Marshal(v.AsString())
+		raw := json.RawMessage(msg)
+		mustAutomationResult(c).Outputs[name] = &raw
+		return nil
+	})
+}
+
+var _ = Describe("control flow", func() {
+
+	var (
+		ctx context.Context
+		res *Result
+	)
+
+	BeforeEach(func() {
+		ctx, res = newTestContext()
+	})
+
+	Describe("ifTask", func() {
+
+		DescribeTable("examples",
+			func(cond string, expected string) {
+				task := ifTask(expression(cond), capture("branch", `"then"`), capture("branch", `"else"`))
+
+				Expect(task.Do(ctx)).To(Succeed())
+				Expect(evalContextFrom(ctx).Variables["branch"]).To(Equal(cty.StringVal(expected)))
+			},
+			Entry("true", "var.show", "then"),
+			Entry("false", "!var.show", "else"),
+			Entry("string converted to bool", `"false"`, "else"),
+		)
+
+		It("returns an error when the condition is not a bool", func() {
+			task := ifTask(expression(`"maybe"`), TaskFunc(nil), TaskFunc(nil))
+			Expect(task.Do(ctx)).To(MatchError(ContainSubstring("invalid condition")))
+		})
+	})
+
+	Describe("forEachTask", func() {
+
+		It("binds each.key and each.value", func() {
+			task := forEachTask("items", evalItems(expression(`["a", "b"]`)), capture("item", `"${each.key}=${each.value}"`))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(evalContextFrom(ctx).Variables["items"]).To(Equal(cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"item": cty.StringVal("0=a")}),
+				cty.ObjectVal(map[string]cty.Value{"item": cty.StringVal("1=b")}),
+			})))
+			Expect(string(*res.Outputs["items"])).To(MatchJSON(`[{"item": "0=a"}, {"item": "1=b"}]`))
+		})
+
+		It("iterates over maps by key", func() {
+			task := forEachTask("items", evalItems(expression(`{ x = 1 }`)), capture("item", `"${each.key}=${each.value}"`))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(string(*res.Outputs["items"])).To(MatchJSON(`[{"item": "x=1"}]`))
+		})
+
+		It("does not leak each into the enclosing scope", func() {
+			task := forEachTask("", evalItems(expression(`["a"]`)), TaskFunc(nil))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(evalContextFrom(ctx).Variables).NotTo(HaveKey("each"))
+		})
+
+		It("keeps the errors caught by each iteration", func() {
+			fail := TaskFunc(func(context.Context) error {
+				return errors.New("element not found")
+			})
+			task := forEachTask("", evalItems(expression(`["a", "b"]`)), tryTask(fail, TaskFunc(nil), TaskFunc(nil)))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(res.Errors).To(Equal([]string{"element not found", "element not found"}))
+		})
+
+		It("keeps the attempts made by each iteration", func() {
+			task := forEachTask("", evalItems(expression(`["a", "b"]`)), retryTask("a.autog:3,3-8", 3, 0, 1, TaskFunc(nil)))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(res.Attempts).To(Equal(map[string][]int{"a.autog:3,3-8": {1, 1}}))
+		})
+
+		It("returns an error when items cannot be iterated", func() {
+			task := forEachTask("", evalItems(expression(`"a"`)), TaskFunc(nil))
+			Expect(task.Do(ctx)).To(MatchError(ContainSubstring("invalid for_each items")))
+		})
+	})
+
+	Describe("tryTask", func() {
+
+		var fail = TaskFunc(func(context.Context) error {
+			return errors.New("element not found")
+		})
+
+		It("runs catch with the error in scope", func() {
+			task := tryTask(fail, capture("message", "error.message"), TaskFunc(nil))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(evalContextFrom(ctx).Variables["message"]).To(Equal(cty.StringVal("element not found")))
+			Expect(evalContextFrom(ctx).Variables).NotTo(HaveKey("error"))
+			Expect(res.Errors).To(Equal([]string{"element not found"}))
+		})
+
+		It("does not run catch when the tasks succeed", func() {
+			task := tryTask(TaskFunc(nil), fail, capture("cleanup", `"done"`))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(evalContextFrom(ctx).Variables["cleanup"]).To(Equal(cty.StringVal("done")))
+			Expect(res.Errors).To(BeEmpty())
+		})
+
+		It("runs finally when catch fails", func() {
+			task := tryTask(fail, fail, capture("cleanup", `"done"`))
+
+			Expect(task.Do(ctx)).To(MatchError("element not found"))
+			Expect(evalContextFrom(ctx).Variables["cleanup"]).To(Equal(cty.StringVal("done")))
+		})
+	})
+
+	Describe("retryTask", func() {
+
+		It("runs the tasks again until they succeed", func() {
+			var calls int
+			task := retryTask("a.autog:3,3-8", 3, time.Millisecond, 2, TaskFunc(func(context.Context) error {
+				calls++
+				if calls < 2 {
+					return errors.New("not ready")
+				}
+				return nil
+			}))
+
+			Expect(task.Do(ctx)).To(Succeed())
+			Expect(calls).To(Equal(2))
+			Expect(res.Attempts).To(Equal(map[string][]int{"a.autog:3,3-8": {2}}))
+		})
+
+		It("returns the last error when the attempts are exhausted", func() {
+			var calls int
+			task := retryTask("a.autog:3,3-8", 3, 0, 1, TaskFunc(func(context.Context) error {
+				calls++
+				return fmt.Errorf("failure %d", calls)
+			}))
+
+			Expect(task.Do(ctx)).To(MatchError("failure 3"))
+			Expect(res.Attempts).To(Equal(map[string][]int{"a.autog:3,3-8": {3}}))
+		})
+
+		It("stops when the context is done", func() {
+			c, cancel := context.WithCancel(ctx)
+			cancel()
+			task := retryTask("a.autog:3,3-8", 3, time.Hour, 1, TaskFunc(func(context.Context) error {
+				return errors.New("not ready")
+			}))
+
+			Expect(task.Do(c)).To(MatchError(context.Canceled))
+			Expect(res.Attempts).To(Equal(map[string][]int{"a.autog:3,3-8": {1}}))
+		})
+	})
+
+	Describe("timeoutTask", func() {
+
+		It("returns an error naming the task when the deadline elapses", func() {
+			task := timeoutTask(time.Millisecond, "click task (a.autog:3,3-8)", TaskFunc(func(c context.Context) error {
+				<-c.Done()
+				return c.Err()
+			}))
+
+			Expect(task.Do(ctx)).To(MatchError("click task (a.autog:3,3-8) timed out after 1ms"))
+		})
+
+		It("returns the error of the task otherwise", func() {
+			task := timeoutTask(time.Hour, "click task", TaskFunc(func(context.Context) error {
+				return errors.New("element not found")
+			}))
+
+			Expect(task.Do(ctx)).To(MatchError("element not found"))
+		})
+	})
+})
+
+var _ = Describe("Driver.flow", func() {
+
+	var (
+		ctx    context.Context
+		driver *Driver
+	)
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+		driver = &Driver{
+			model: &model.Model{
+				Automations: []*model.Automation{
+					{Name: "login", Params: []string{"user"}, Returns: expression(`{ greeting = greeting }`)},
+					{Name: "loop"},
+					{Name: "element"},
+					{Name: "run", Package: "shared", Returns: expression(`{ from = from }`)},
+					{Name: "logout", Package: "shared", Returns: expression(`{ from = from }`)},
+					{Name: "logout", Package: "util", Returns: expression(`{ from = from }`)},
+				},
+				Packages: []string{"shared", "util"},
+			},
+			automations: map[string]*Automation{
+				"login": {Tasks: []Task{capture("greeting", `"hello ${param.user} ${var.show}"`)}},
+				"element": {Tasks: []Task{TaskFunc(func(c context.Context) error {
+					if scopeNodeFrom(c) != nil {
+						return errors.New("unexpected scope node")
+					}
+					return nil
+				})}},
+				"@shared/logout": {Tasks: []Task{capture("from", `"shared"`)}},
+				"@util/logout":   {Tasks: []Task{capture("from", `"util"`)}},
+			},
+		}
+		driver.automations["loop"] = &Automation{Tasks: []Task{driver.flow("loop", nil)}}
+		driver.automations["@shared/run"] = &Automation{Tasks: []Task{driver.flow("logout", nil)}}
+	})
+
+	It("resolves names within the package of the caller", func() {
+		task := driver.flow("@shared/run", nil)
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(evalContextFrom(ctx).Variables["from"]).To(Equal(cty.StringVal("shared")))
+	})
+
+	It("resolves names within the package of the context", func() {
+		task := driver.flow("logout", nil)
+
+		Expect(task.Do(withPackage(ctx, "util"))).To(Succeed())
+		Expect(evalContextFrom(ctx).Variables["from"]).To(Equal(cty.StringVal("util")))
+	})
+
+	It("does not run within the element of the caller", func() {
+		task := driver.flow("element", nil)
+		Expect(task.Do(withScopeNode(ctx, &cdp.Node{NodeID: 1}))).To(Succeed())
+	})
+
+	It("captures the returns in the scope of the caller", func() {
+		task := driver.flow("login", expression(`{ user = "admin" }`))
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(evalContextFrom(ctx).Variables["greeting"]).To(Equal(cty.StringVal("hello admin true")))
+	})
+
+	It("isolates the scope of the automation", func() {
+		evalContextFrom(ctx).Variables["local_value"] = cty.True
+		driver.automations["login"] = &Automation{Tasks: []Task{capture("greeting", "local_value")}}
+		task := driver.flow("login", expression(`{ user = "admin" }`))
+
+		Expect(task.Do(ctx)).To(MatchError(ContainSubstring("Unknown variable")))
+	})
+
+	DescribeTable("errors",
+		func(name string, args string, expected string) {
+			var argsExpr model.Expression
+			if args != "" {
+				argsExpr = expression(args)
+			}
+			Expect(driver.flow(name, argsExpr).Do(ctx)).To(MatchError(expected))
+		},
+		Entry("missing argument", "login", "", `flow "login": missing argument "user"`),
+		Entry("unexpected argument", "login", `{ user = "a", role = "b" }`, `flow "login": unexpected argument "role"`),
+		Entry("recursion", "loop", "", "recursive flow: loop -> loop"),
+		Entry("not found", "missing", "", `automation not found "missing"`),
+		Entry("ambiguous outside of the packages", "logout", "", `automation "logout" is ambiguous; use one of @shared/logout, @util/logout`),
+	)
+})
+
+var _ = Describe("withFunctions", func() {
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+	})
+
+	It("provides the functions to each evaluation context", func() {
+		c := withEvalContext(withFunctions(ctx, model.Functions(".")), nil)
+		c = withScope(c, map[string]cty.Value{"name": cty.StringVal("admin")})
+
+		Expect(evalString(c, expression("upper(name)"))).To(Equal("ADMIN"))
+	})
+})
+
+var _ = Describe("evalQueryOptions", func() {
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+	})
+
+	It("evaluates the options in scope", func() {
+		evalContextFrom(ctx).Variables["wait"] = cty.StringVal("2s")
+		opts, err := evalQueryOptions(ctx, &model.Options{
+			RetryInterval: expression(`"${wait}"`),
+			AtLeast:       expression("1 + 1"),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*opts.retryInterval).To(Equal(2 * time.Second))
+		Expect(*opts.atLeast).To(Equal(2))
+		Expect(opts.timeout).To(Equal(model.DefaultQueryTimeout))
+	})
+
+	It("returns an error naming the invalid option", func() {
+		_, err := evalQueryOptions(ctx, &model.Options{Timeout: expression(`"soon"`)})
+		Expect(err).To(MatchError(ContainSubstring("invalid timeout")))
+	})
+})
+
+var _ = Describe("evalTargets", func() {
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+	})
+
+	It("evaluates the target of each selector", func() {
+		evalContextFrom(ctx).Variables["row"] = cty.NumberIntVal(3)
+		targets, err := evalTargets(ctx, []*model.Selector{
+			{Target: expression(`"#row-${row}"`)},
+			{Target: model.Literal(cty.StringVal("#literal"))},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(Equal([]string{"#row-3", "#literal"}))
+	})
+})
+
+var _ = Describe("outputsTask", func() {
+
+	var (
+		ctx context.Context
+		res *Result
+	)
+
+	BeforeEach(func() {
+		ctx, res = newTestContext()
+	})
+
+	It("replaces the captured values with the outputs", func() {
+		task := tasks(
+			capture("title", `"Example Domain"`),
+			outputsTask([]*model.Output{
+				{Name: "page", Value: expression(`{ title = title, show = var.show }`)},
+				{Name: "token", Value: expression(`"secret"`), Sensitive: true},
+			}),
+		)
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(res.Outputs).To(HaveLen(2))
+		Expect(string(*res.Outputs["page"])).To(MatchJSON(`{"title": "Example Domain", "show": true}`))
+		Expect(string(*res.Outputs["token"])).To(MatchJSON(`"(sensitive value)"`))
+	})
+
+	It("keeps the captured values when there are no outputs", func() {
+		task := tasks(capture("title", `"Example Domain"`), outputsTask(nil))
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(res.Outputs).To(HaveKey("title"))
+	})
+
+	It("returns an error naming the output", func() {
+		task := outputsTask([]*model.Output{{Name: "page", Value: expression("missing")}})
+		Expect(task.Do(ctx)).To(MatchError(ContainSubstring(`output "page"`)))
+	})
+
+	It("produces the outputs when the run has a timeout", func() {
+		d := &Driver{timeout: time.Minute}
+		task := d.runTask(capture("title", `"Example Domain"`), []*model.Output{
+			{Name: "page", Value: expression("title")},
+		})
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(res.Outputs).To(HaveLen(1))
+		Expect(string(*res.Outputs["page"])).To(MatchJSON(`"Example Domain"`))
+	})
+})
+
+var _ = Describe("bindAssert", func() {
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+	})
+
+	It("succeeds when the condition holds", func() {
+		task := bindAssert(&model.Assert{Condition: expression("var.show")})
+		Expect(task.Do(ctx)).To(Succeed())
+	})
+
+	It("reports a diagnostic at the declaration", func() {
+		task := bindAssert(&model.Assert{
+			Condition: expression("!var.show"),
+			Message:   expression(`"banner should be hidden"`),
+			DeclRange: hcl.Range{Filename: "a.autog", Start: hcl.Pos{Line: 3, Column: 3}, End: hcl.Pos{Line: 3, Column: 9}},
+		})
+
+		err := task.Do(ctx)
+		Expect(err).To(BeAssignableToTypeOf(hcl.Diagnostics{}))
+		Expect(err).To(MatchError("a.autog:3,3-9: Assertion failed; banner should be hidden (condition is false)"))
+	})
+
+	It("reports an error without a declaration", func() {
+		task := bindAssert(&model.Assert{Condition: expression("false")})
+		Expect(task.Do(ctx)).To(MatchError("assertion failed: condition is false"))
+	})
+})
+
+var _ = Describe("extract", func() {
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx, _ = newTestContext()
+	})
+
+	Describe("extractPages", func() {
+
+		var page = func(pages ...[]string) func(context.Context) ([]cty.Value, []*cdp.Node, error) {
+			n := 0
+			return func(context.Context) ([]cty.Value, []*cdp.Node, error) {
+				var rows []cty.Value
+				var roots []*cdp.Node
+				for _, name := range pages[n] {
+					rows = append(rows, cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name)}))
+					roots = append(roots, &cdp.Node{NodeID: cdp.NodeID(len(roots) + 1)})
+				}
+				n++
+				return rows, roots, nil
+			}
+		}
+
+		var nextUntil = func(pages int) (func(context.Context, []*cdp.Node) (bool, error), *int) {
+			calls := 0
+			return func(context.Context, []*cdp.Node) (bool, error) {
+				calls++
+				return calls < pages, nil
+			}, &calls
+		}
+
+		It("follows next until there are no more pages", func() {
+			next, calls := nextUntil(2)
+			rows, err := extractPages(ctx, 10, page([]string{"a", "b"}, []string{"c"}), next)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(HaveLen(3))
+			Expect(*calls).To(Equal(2))
+		})
+
+		It("stops at the maximum number of pages", func() {
+			next, calls := nextUntil(10)
+			rows, err := extractPages(ctx, 2, page([]string{"a"}, []string{"b"}, []string{"c"}), next)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(HaveLen(2))
+			Expect(*calls).To(Equal(1))
+		})
+
+		It("stops at a page without rows", func() {
+			next, calls := nextUntil(10)
+			rows, err := extractPages(ctx, 10, page([]string{}), next)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(BeEmpty())
+			Expect(*calls).To(Equal(0))
+		})
+
+		It("returns an error naming the page which could not be loaded", func() {
+			next := func(context.Context, []*cdp.Node) (bool, error) {
+				return false, errors.New("element not found")
+			}
+			_, err := extractPages(ctx, 10, page([]string{"a"}), next)
+
+			Expect(err).To(MatchError("loading page 2: element not found"))
+		})
+	})
+
+	Describe("fieldValue", func() {
+
+		DescribeTable("examples",
+			func(ty cty.Type, text string, found bool, expected cty.Value) {
+				v, err := fieldValue(&model.ExtractField{Name: "f", Type: ty}, text, found)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(v.RawEquals(expected)).To(BeTrue(), v.GoString())
+			},
+			Entry("string", cty.String, "Widget", true, cty.StringVal("Widget")),
+			Entry("empty string", cty.String, "", true, cty.StringVal("")),
+			Entry("number", cty.Number, "9.5", true, cty.NumberFloatVal(9.5)),
+			Entry("empty number", cty.Number, "", true, cty.NullVal(cty.Number)),
+			Entry("bool", cty.Bool, "true", true, cty.True),
+			Entry("not found", cty.String, "", false, cty.NullVal(cty.String)),
+			Entry("default type", cty.NilType, "Widget", true, cty.StringVal("Widget")),
+		)
+
+		It("returns an error naming the field which cannot be converted", func() {
+			_, err := fieldValue(&model.ExtractField{Name: "price", Type: cty.Number}, "$9.50", true)
+			Expect(err).To(MatchError(ContainSubstring(`invalid value of field "price"`)))
+		})
+	})
+
+	Describe("extractList", func() {
+
+		It("has the type of the fields when it is empty", func() {
+			v := extractList([]*model.ExtractField{
+				{Name: "name", Type: cty.String},
+				{Name: "price", Type: cty.Number},
+			}, nil)
+
+			Expect(v.Type()).To(Equal(cty.List(cty.Object(map[string]cty.Type{
+				"name":  cty.String,
+				"price": cty.Number,
+			}))))
+			Expect(v.LengthInt()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("usingValue", func() {
+
+	var (
+		ctx context.Context
+		res *Result
+	)
+
+	BeforeEach(func() {
+		ctx, res = newTestContext()
+	})
+
+	It("captures the value and its JSON", func() {
+		list := cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("Widget"),
+				"price": cty.NumberIntVal(3),
+			}),
+		})
+		task := usingValue("products", func(v *cty.Value) chromedp.Action {
+			return TaskFunc(func(context.Context) error {
+				*v = list
+				return nil
+			})
+		})
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(evalContextFrom(ctx).Variables["products"]).To(Equal(list))
+		Expect(string(*res.Outputs["products"])).To(Equal(`[{"name":"Widget","price":3}]`))
+	})
+})
+
+var _ = Describe("attributeValue", func() {
+
+	var (
+		ctx context.Context
+		res *Result
+	)
+
+	BeforeEach(func() {
+		ctx, res = newTestContext()
+	})
+
+	It("captures the value of the attribute", func() {
+		Expect(attributeValue("", true)).To(Equal(cty.StringVal("")))
+	})
+
+	It("captures null when the element does not have the attribute", func() {
+		task := usingValue("href", func(v *cty.Value) chromedp.Action {
+			return TaskFunc(func(context.Context) error {
+				*v = attributeValue("", false)
+				return nil
+			})
+		})
+
+		Expect(task.Do(ctx)).To(Succeed())
+		Expect(evalContextFrom(ctx).Variables["href"]).To(Equal(cty.NullVal(cty.String)))
+		Expect(string(*res.Outputs["href"])).To(Equal("null"))
+	})
+})
+
+var _ = Describe("secretFunc", func() {
+
+	var (
+		mask *secretMask
+		c    context.Context
+	)
+
+	BeforeEach(func() {
+		ctx, _ := newTestContext()
+		mask = &secretMask{}
+		secrets := SecretProviderFunc(func(name string) (string, bool, error) {
+			if name == "password" {
+				return `p"ssw0rd`, true, nil
+			}
+			return "", false, nil
+		})
+		c = withEvalContext(withFunctions(ctx, map[string]function.Function{
+			"secret": secretFunc(secrets, mask),
+		}), nil)
+	})
+
+	It("reveals the value of the secret", func() {
+		Expect(evalString(c, expression(`secret("password")`))).To(Equal(`p"ssw0rd`))
+	})
+
+	It("returns an error when the secret is not found", func() {
+		_, err := evalString(c, expression(`secret("missing")`))
+		Expect(err).To(MatchError(ContainSubstring(`secret "missing" not found`)))
+	})
+
+	It("masks revealed values in messages and outputs", func() {
+		_, _ = evalString(c, expression(`secret("password")`))
+		Expect(mask.apply(`login with p"ssw0rd`)).To(Equal("login with ***"))
+
+		raw := json.RawMessage(`{"password":"p\"ssw0rd"}`)
+		outputs := map[string]*json.RawMessage{"login": &raw}
+		mask.applyOutputs(outputs)
+		Expect(string(*outputs["login"])).To(Equal(`{"password":"***"}`))
+	})
+})
+
+var _ = Describe("Allocator.withBrowser", func() {
+
+	It("applies the profile where options are not set explicitly", func() {
+		a := &Allocator{}
+		Expect(a.SetExecPath("/usr/bin/chrome")).To(Succeed())
+		Expect(a.SetEnv([]string{"TZ=UTC"})).To(Succeed())
+		Expect(a.SetFlag([]string{"lang=fr"})).To(Succeed())
+
+		exec, headless := "/usr/bin/chromium", true
+		res := a.withBrowser(&model.Browser{
+			ExecPath: &exec,
+			Headless: &headless,
+			Env:      []string{"TZ=America/New_York"},
+			Flags:    map[string]any{"lang": "en-US", "mute-audio": true},
+		})
+
+		Expect(*res.Options.ExecPath).To(Equal("/usr/bin/chrome"))
+		Expect(*res.Options.Headless).To(BeTrue())
+		Expect(res.Options.Env).To(Equal([]string{"TZ=America/New_York", "TZ=UTC"}))
+		Expect(res.Options.Flags).To(Equal(map[string]any{"lang": "fr", "mute-audio": true}))
+		Expect(a.Options.Headless).To(BeNil())
+	})
+
+	It("uses the remote URL of the profile", func() {
+		url := "ws://127.0.0.1:9222"
+		res := (&Allocator{}).withBrowser(&model.Browser{RemoteURL: &url})
+		Expect(res.BrowserURL).To(Equal(url))
+	})
+})
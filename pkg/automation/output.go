@@ -0,0 +1,59 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// redacted is written to the result in place of the value of a sensitive
+// output
+const redacted = `"(sensitive value)"`
+
+// outputsTask evaluates the outputs declared by an automation, which replace
+// the values captured in the result. When there are no outputs, the captured
+// values are kept.
+func outputsTask(outputs []*model.Output) Task {
+	return TaskFunc(func(c context.Context) error {
+		if len(outputs) == 0 {
+			return nil
+		}
+
+		values := make(map[string]*json.RawMessage, len(outputs))
+		for _, o := range outputs {
+			msg, err := evalOutput(c, o)
+			if err != nil {
+				return fmt.Errorf("output %q: %w", o.Name, err)
+			}
+			values[o.Name] = &msg
+		}
+
+		mustAutomationResult(c).Outputs = values
+		return nil
+	})
+}
+
+func evalOutput(c context.Context, o *model.Output) (json.RawMessage, error) {
+	v, err := evalContext(c, o.Value)
+	if err != nil {
+		return nil, err
+	}
+	if o.Sensitive {
+		return json.RawMessage(redacted), nil
+	}
+	if v == cty.NilVal || v.IsNull() {
+		return json.RawMessage("null"), nil
+	}
+	if !v.IsWhollyKnown() {
+		return nil, fmt.Errorf("value is not known")
+	}
+	return ctyjson.Marshal(v, v.Type())
+}
@@ -15,12 +15,21 @@ import (
 type Result struct {
 	Outputs     map[string]*json.RawMessage
 	OutputFiles map[string]*[]byte
+
+	// Errors contains the messages of errors that were caught by try blocks
+	Errors []string
+
+	// Attempts contains the number of attempts made by the retry blocks, keyed
+	// by their location. A block which ran more than once, such as within a
+	// for_each, has the number of attempts of each time that it ran.
+	Attempts map[string][]int
 }
 
 func newResult() *Result {
 	return &Result{
 		Outputs:     map[string]*json.RawMessage{},
 		OutputFiles: map[string]*[]byte{},
+		Attempts:    map[string][]int{},
 	}
 }
 
@@ -0,0 +1,170 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// SecretProvider resolves the values of secrets by name. A provider which
+// does not contain the secret returns false rather than an error.
+type SecretProvider interface {
+	Secret(name string) (string, bool, error)
+}
+
+// SecretProviderFunc implements a secret provider from a function
+type SecretProviderFunc func(name string) (string, bool, error)
+
+// EnvSecretProvider resolves secrets from environment variables. The name of
+// the variable is the prefix followed by the name of the secret in upper case,
+// where characters other than letters and digits are replaced by underscores.
+type EnvSecretProvider struct {
+	// Prefix is prepended to the name of the variable. When empty,
+	// DefaultSecretEnvPrefix is used.
+	Prefix string
+}
+
+// DefaultSecretEnvPrefix is the prefix of environment variables which
+// provide secrets
+const DefaultSecretEnvPrefix = "AUTOGUN_SECRET_"
+
+// maskedValue is written in place of the values of secrets
+const maskedValue = "***"
+
+// secretMask records the values of secrets which have been revealed so that
+// they can be masked in logs and outputs
+type secretMask struct {
+	mu     sync.Mutex
+	values []string
+}
+
+// SecretProviders combines the providers so that each is consulted in order
+// until one of them contains the secret
+func SecretProviders(providers ...SecretProvider) SecretProvider {
+	return SecretProviderFunc(func(name string) (string, bool, error) {
+		for _, p := range providers {
+			if p == nil {
+				continue
+			}
+			value, ok, err := p.Secret(name)
+			if err != nil || ok {
+				return value, ok, err
+			}
+		}
+		return "", false, nil
+	})
+}
+
+func (f SecretProviderFunc) Secret(name string) (string, bool, error) {
+	return f(name)
+}
+
+func (p EnvSecretProvider) Secret(name string) (string, bool, error) {
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = DefaultSecretEnvPrefix
+	}
+	value, ok := os.LookupEnv(prefix + envName(name))
+	return value, ok, nil
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// secretFunc provides the secret function, which reveals the value of a
+// secret and records it in the mask
+func secretFunc(p SecretProvider, mask *secretMask) function.Function {
+	return function.New(&function.Spec{
+		Description: "Gets the value of a secret from the secret providers. The value is masked in logs and outputs.",
+		Params: []function.Parameter{
+			{Name: "name", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			name := args[0].AsString()
+			if p == nil {
+				return cty.UnknownVal(cty.String), fmt.Errorf("secret %q: no secret providers are configured", name)
+			}
+			value, ok, err := p.Secret(name)
+			if err != nil {
+				return cty.UnknownVal(cty.String), fmt.Errorf("secret %q: %w", name, err)
+			}
+			if !ok {
+				return cty.UnknownVal(cty.String), fmt.Errorf("secret %q not found", name)
+			}
+			mask.add(value)
+			return cty.StringVal(value), nil
+		},
+	})
+}
+
+func (m *secretMask) add(value string) {
+	if m == nil || value == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values = append(m.values, value)
+}
+
+// apply replaces the values of secrets within the text
+func (m *secretMask) apply(text string) string {
+	if m == nil {
+		return text
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, v := range m.values {
+		text = strings.ReplaceAll(text, v, maskedValue)
+	}
+	return text
+}
+
+// applyOutputs replaces the values of secrets within the outputs, which are
+// matched as they appear in JSON
+func (m *secretMask) applyOutputs(outputs map[string]*json.RawMessage) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range outputs {
+		if msg == nil {
+			continue
+		}
+		text := string(*msg)
+		for _, v := range m.values {
+			quoted, _ := json.Marshal(v)
+			text = strings.ReplaceAll(text, string(quoted[1:len(quoted)-1]), maskedValue)
+		}
+		*msg = json.RawMessage(text)
+	}
+}
+
+func withSecretMask(c context.Context, mask *secretMask) context.Context {
+	return context.WithValue(c, secretMaskKey, mask)
+}
+
+func secretMaskFrom(c context.Context) *secretMask {
+	mask, _ := c.Value(secretMaskKey).(*secretMask)
+	return mask
+}
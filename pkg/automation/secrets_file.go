@@ -0,0 +1,135 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// FileSecretProvider resolves secrets from a local file which is encrypted
+// using a passphrase. A file which does not exist contains no secrets. The
+// file is created by the secrets set command or by [WriteSecretsFile].
+type FileSecretProvider struct {
+	Path       string
+	Passphrase string
+
+	once    sync.Once
+	secrets map[string]string
+	err     error
+}
+
+// DefaultSecretsFile is the name of the encrypted secrets file within the
+// directory where workspace metadata is stored
+const DefaultSecretsFile = "secrets.enc"
+
+const pbkdf2Iterations = 600_000
+
+// secretsFile is the format of the encrypted secrets file
+type secretsFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func (p *FileSecretProvider) Secret(name string) (string, bool, error) {
+	p.once.Do(func() {
+		p.secrets, p.err = ReadSecretsFile(p.Path, p.Passphrase)
+		if errors.Is(p.err, fs.ErrNotExist) {
+			p.err = nil
+		}
+	})
+	if p.err != nil {
+		return "", false, p.err
+	}
+	value, ok := p.secrets[name]
+	return value, ok, nil
+}
+
+// ReadSecretsFile decrypts the secrets stored in the file
+func ReadSecretsFile(path string, passphrase string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("a passphrase is required to read secrets file %s", path)
+	}
+
+	var file secretsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", path, err)
+	}
+
+	aead, err := secretsCipher(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt secrets file %s: wrong passphrase or corrupted file", path)
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// WriteSecretsFile encrypts the secrets using the passphrase and stores them
+// in the file, replacing its contents
+func WriteSecretsFile(path string, passphrase string, secrets map[string]string) error {
+	if passphrase == "" {
+		return fmt.Errorf("a passphrase is required to write secrets file %s", path)
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	file := secretsFile{
+		Salt: make([]byte, 16),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	aead, err := secretsCipher(passphrase, file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = aead.Seal(nil, file.Nonce, plain, nil)
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func secretsCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
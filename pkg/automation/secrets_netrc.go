@@ -0,0 +1,131 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+)
+
+// NetrcSecretProvider resolves secrets from the passwords in a .netrc file.
+// The name of the secret is the name of the machine, or LOGIN@MACHINE to
+// select the entry for a particular login. The default entry is used when no
+// machine matches. A file which does not exist contains no secrets.
+type NetrcSecretProvider struct {
+	Path string
+
+	once    sync.Once
+	entries []netrcEntry
+	err     error
+}
+
+type netrcEntry struct {
+	machine   string
+	login     string
+	password  string
+	isDefault bool
+}
+
+func (p *NetrcSecretProvider) Secret(name string) (string, bool, error) {
+	p.once.Do(func() {
+		p.entries, p.err = readNetrc(p.Path)
+		if errors.Is(p.err, fs.ErrNotExist) {
+			p.err = nil
+		}
+	})
+	if p.err != nil {
+		return "", false, p.err
+	}
+
+	login, machine, hasLogin := strings.Cut(name, "@")
+	if !hasLogin {
+		machine, login = name, ""
+	}
+	matches := func(e netrcEntry) bool {
+		return login == "" || e.login == login
+	}
+	for _, e := range p.entries {
+		if !e.isDefault && e.machine == machine && matches(e) {
+			return e.password, true, nil
+		}
+	}
+	for _, e := range p.entries {
+		if e.isDefault && matches(e) {
+			return e.password, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// readNetrc parses the entries of a .netrc file. Macro definitions are
+// skipped.
+func readNetrc(path string) ([]netrcEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		entries []netrcEntry
+		current *netrcEntry
+		tokens  []string
+		scanner = bufio.NewScanner(f)
+		inMacro bool
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// A macro definition ends at a blank line
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "macdef" {
+			inMacro = true
+			continue
+		}
+		if i := strings.Index(line, "#"); i >= 0 {
+			fields = strings.Fields(line[:i])
+		}
+		tokens = append(tokens, fields...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		value := func() string {
+			if i+1 < len(tokens) {
+				i++
+				return tokens[i]
+			}
+			return ""
+		}
+		switch tokens[i] {
+		case "machine":
+			entries = append(entries, netrcEntry{machine: value()})
+			current = &entries[len(entries)-1]
+		case "default":
+			entries = append(entries, netrcEntry{isDefault: true})
+			current = &entries[len(entries)-1]
+		case "login":
+			if v := value(); current != nil {
+				current.login = v
+			}
+		case "password":
+			if v := value(); current != nil {
+				current.password = v
+			}
+		case "account":
+			value()
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Carbonfrost/autogun/pkg/automation"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecretProvider", func() {
+
+	Describe("EnvSecretProvider", func() {
+
+		It("reads the variable derived from the name", func() {
+			GinkgoT().Setenv("AUTOGUN_SECRET_API_TOKEN", "abc")
+
+			value, ok, err := automation.EnvSecretProvider{}.Secret("api-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("abc"))
+		})
+	})
+
+	Describe("FileSecretProvider", func() {
+
+		It("reads secrets written to the file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "secrets.enc")
+			Expect(automation.WriteSecretsFile(path, "key", map[string]string{"api": "abc"})).To(Succeed())
+
+			p := &automation.FileSecretProvider{Path: path, Passphrase: "key"}
+			value, ok, err := p.Secret("api")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("abc"))
+		})
+
+		It("returns an error when the passphrase is wrong", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "secrets.enc")
+			Expect(automation.WriteSecretsFile(path, "key", map[string]string{"api": "abc"})).To(Succeed())
+
+			p := &automation.FileSecretProvider{Path: path, Passphrase: "wrong"}
+			_, _, err := p.Secret("api")
+			Expect(err).To(MatchError(ContainSubstring("cannot decrypt secrets file")))
+		})
+
+		It("contains no secrets when the file does not exist", func() {
+			p := &automation.FileSecretProvider{Path: filepath.Join(GinkgoT().TempDir(), "secrets.enc")}
+			_, ok, err := p.Secret("api")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("NetrcSecretProvider", func() {
+
+		var p *automation.NetrcSecretProvider
+
+		BeforeEach(func() {
+			path := filepath.Join(GinkgoT().TempDir(), ".netrc")
+			Expect(os.WriteFile(path, []byte(`
+machine example.com login alice password one
+machine example.com
+  login bob
+  password two
+
+macdef init
+machine ignored.com password three
+
+default login anonymous password guest
+`), 0600)).To(Succeed())
+			p = &automation.NetrcSecretProvider{Path: path}
+		})
+
+		DescribeTable("examples",
+			func(name string, expected string) {
+				value, ok, err := p.Secret(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ok).To(BeTrue())
+				Expect(value).To(Equal(expected))
+			},
+			Entry("machine", "example.com", "one"),
+			Entry("login at machine", "bob@example.com", "two"),
+			Entry("default", "", "guest"),
+			Entry("default for another machine", "other.com", "guest"),
+			Entry("default for its login", "anonymous@other.com", "guest"),
+		)
+
+		It("skips macro definitions", func() {
+			value, _, _ := p.Secret("ignored.com")
+			Expect(value).To(Equal("guest"))
+		})
+
+		It("does not use the default for another login", func() {
+			_, ok, err := p.Secret("carol@other.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("SecretProviders", func() {
+
+		It("consults each provider in order", func() {
+			p := automation.SecretProviders(
+				automation.SecretProviderFunc(func(string) (string, bool, error) { return "", false, nil }),
+				automation.SecretProviderFunc(func(name string) (string, bool, error) { return name + "!", true, nil }),
+			)
+			value, ok, _ := p.Secret("x")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("x!"))
+		})
+	})
+})
@@ -13,8 +13,8 @@ import (
 // Print returns a Task that writes its operands to stderr, formatting them in
 // the manner of fmt.Print and appending a newline.
 func Print(args ...any) Task {
-	return TaskFunc(func(context.Context) error {
-		fmt.Fprintln(os.Stderr, args...)
+	return TaskFunc(func(c context.Context) error {
+		fmt.Fprint(os.Stderr, secretMaskFrom(c).apply(fmt.Sprintln(args...)))
 		return nil
 	})
 }
@@ -22,9 +22,8 @@ func Print(args ...any) Task {
 // Printf returns a Task that writes a formatted message to stderr, formatting
 // it in the manner of fmt.Printf and appending a newline.
 func Printf(format string, args ...any) Task {
-	return TaskFunc(func(context.Context) error {
-		fmt.Fprintf(os.Stderr, format, args...)
-		fmt.Fprintln(os.Stderr)
+	return TaskFunc(func(c context.Context) error {
+		fmt.Fprintln(os.Stderr, secretMaskFrom(c).apply(fmt.Sprintf(format, args...)))
 		return nil
 	})
 }
@@ -37,12 +37,22 @@ func NewApp() *cli.App {
 		),
 		Flags: []*cli.Flag{
 			{Uses: workspace.ListDevices()},
+			{Uses: workspace.ListFunctions()},
 			{Uses: SetVerbose()},
 		},
 		Commands: []*cli.Command{
 			{Uses: workspace.Fmt()},
+			{Uses: workspace.Convert()},
 			{Uses: workspace.Run()},
 			{Uses: workspace.Check()},
+			{Uses: workspace.LSP()},
+			{
+				Uses: workspace.Secrets(),
+				Subcommands: []*cli.Command{
+					{Uses: workspace.SetSecret()},
+					{Uses: workspace.RemoveSecret()},
+				},
+			},
 		},
 		Version: build.Version.Version,
 	}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Assert verifies the state of the page and fails the automation when any of
+// its checks do not hold. The text, count, and attribute checks apply to the
+// elements matched by its selectors.
+type Assert struct {
+	DeclRange    hcl.Range
+	Condition    hcl.Expression
+	Title        hcl.Expression
+	TitleMatches hcl.Expression
+	TextContains hcl.Expression
+	Count        hcl.Expression
+	Attribute    string
+	Value        hcl.Expression
+	Message      hcl.Expression
+	Selector     hcl.Expression
+	Selectors    []*Selector
+	Options      *Options
+}
+
+var (
+	assertBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+			{Name: "condition"},
+			{Name: "title"},
+			{Name: "title_matches"},
+			{Name: "text_contains"},
+			{Name: "count"},
+			{Name: "attribute"},
+			{Name: "value"},
+			{Name: "message"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+)
+
+func decodeAssertBlock(block *hcl.Block) (*Assert, hcl.Diagnostics) {
+	a := new(Assert)
+	res, diags := reduceTask(
+		a,
+		block,
+		supportsDeclRange(&a.DeclRange),
+		supportsPartialContentSchema(
+			assertBlockSchema,
+			withAttributeExpression("condition", &a.Condition),
+			withAttributeExpression("title", &a.Title),
+			withAttributeExpression("title_matches", &a.TitleMatches),
+			withAttributeExpression("text_contains", &a.TextContains),
+			withAttributeExpression("count", &a.Count),
+			withAttribute("attribute", &a.Attribute),
+			withAttributeExpression("value", &a.Value),
+			withAttributeExpression("message", &a.Message),
+			withSelectorAttribute(&a.Selector, &a.Selectors),
+			supportsSelectorBlocks(&a.Selectors, &a.Options),
+		),
+	)
+
+	var (
+		hasSelector  = a.Selector != nil || len(a.Selectors) > 0
+		elementCheck = a.TextContains != nil || a.Count != nil || a.Attribute != ""
+		pageCheck    = a.Condition != nil || a.Title != nil || a.TitleMatches != nil
+	)
+	invalid := func(detail string) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid assert block",
+			Detail:   detail,
+			Subject:  &block.DefRange,
+		})
+	}
+
+	switch {
+	case !elementCheck && !pageCheck:
+		invalid("At least one of condition, title, title_matches, text_contains, count, or attribute must be specified.")
+	case elementCheck && !hasSelector:
+		invalid("A selector must be specified to check text_contains, count, or attribute.")
+	case a.Value != nil && a.Attribute == "":
+		invalid("An attribute must be specified to check its value.")
+	}
+	return res, diags
+}
+
+func (*Assert) taskSigil() {}
@@ -4,19 +4,36 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 )
 
+// Automation is a named sequence of tasks. When run by a flow task, the
+// automation receives the values of its params from the args of the flow,
+// and the values of its returns are captured by the caller. Its outputs
+// determine the values written to the result.
 type Automation struct {
 	DeclRange hcl.Range
 	NameRange hcl.Range
 	Name      string
+	Timeout   time.Duration
+	Params    []string
+	Returns   hcl.Expression
+	Outputs   []*Output
 	Tasks     []Task
 }
 
 var (
 	automationBlockSchema = &hcl.BodySchema{
-		Attributes: []hcl.AttributeSchema{},
+		Attributes: []hcl.AttributeSchema{
+			{Name: "timeout"},
+			{Name: "params"},
+			{Name: "returns"},
+		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{
 				Type: "navigate",
@@ -39,6 +56,30 @@ var (
 				Type:       "inner_html",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "outer_html",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "text",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "attribute",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "value",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "count",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "extract",
+				LabelNames: []string{"name"},
+			},
 			{
 				Type: "blur",
 			},
@@ -73,25 +114,65 @@ var (
 			{
 				Type: "version",
 			},
+			{
+				Type:       "flow",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "assert",
+			},
+			{
+				Type: "if",
+			},
+			{
+				Type:       "for_each",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "try",
+			},
+			{
+				Type: "retry",
+			},
 		},
 	}
 
+	// automationBodySchema contains the blocks which can appear directly
+	// within an automation in addition to its tasks
+	automationBodySchema = &hcl.BodySchema{
+		Attributes: automationBlockSchema.Attributes,
+		Blocks: slices.Concat(automationBlockSchema.Blocks, []hcl.BlockHeaderSchema{
+			{
+				Type:       "output",
+				LabelNames: []string{"name"},
+			},
+		}),
+	}
+
 	mappingTaskBlocks = blockMapping[Task]{
+		"assert":           taskMapping(decodeAssertBlock),
+		"attribute":        taskMapping(decodeAttributeBlock),
 		"blur":             taskMapping(decodeBlurBlock),
 		"clear":            taskMapping(decodeClearBlock),
 		"click":            taskMapping(decodeClickBlock),
+		"count":            taskMapping(decodeCountBlock),
 		"double_click":     taskMapping(decodeDoubleClickBlock),
 		"eval":             taskMapping(decodeEvalBlock),
+		"extract":          taskMapping(decodeExtractBlock),
+		"flow":             taskMapping(decodeFlowBlock),
 		"inner_html":       taskMapping(decodeInnerHTMLBlock),
 		"navigate":         taskMapping(decodeNavigateBlock),
 		"navigate_back":    taskMapping(decodeNavigateBackBlock),
 		"navigate_forward": taskMapping(decodeNavigateForwardBlock),
+		"outer_html":       taskMapping(decodeOuterHTMLBlock),
 		"screenshot":       taskMapping(decodeScreenshotBlock),
 		"send_keys":        taskMapping(decodeSendKeysBlock),
 		"reload":           taskMapping(decodeReloadBlock),
 		"sleep":            taskMapping(decodeSleepBlock),
 		"stop":             taskMapping(decodeStopBlock),
+		"text":             taskMapping(decodeTextBlock),
 		"title":            taskMapping(decodeTitleBlock),
+		"value":            taskMapping(decodeValueBlock),
 		"wait_visible":     taskMapping(decodeWaitVisibleBlock),
 		"version":          taskMapping(decodeVersionBlock),
 	}
@@ -99,14 +180,38 @@ var (
 
 func decodeAutomationBlock(block *hcl.Block) (*Automation, hcl.Diagnostics) {
 	f := new(Automation)
-	return reduceTask(
+	res, diags := reduceTask(
 		f,
 		block,
 		supportsDeclRange(&f.DeclRange),
 		supportsOptionalLabel(&f.Name, &f.NameRange),
 		supportsPartialContentSchema(
-			automationBlockSchema,
+			automationBodySchema,
+			withAttributeParser("timeout", f.setTimeout, time.ParseDuration),
+			withAttr("params", f.decodeParams),
+			withAttributeExpression("returns", &f.Returns),
+			appendsTo(&f.Outputs, mappingOutputBlocks),
 			appendsTo(&f.Tasks, mappingTaskBlocks),
 		),
 	)
+	return res, append(diags, checkDuplicateOutputs(f.Outputs)...)
+}
+
+func (a *Automation) decodeParams(attr *hcl.Attribute) hcl.Diagnostics {
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &a.Params)
+	for _, name := range a.Params {
+		if err := checkName(name); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid param name %q", name),
+				Detail:   err.Error(),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
+func (a *Automation) setTimeout(d time.Duration) {
+	a.Timeout = d
 }
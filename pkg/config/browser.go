@@ -0,0 +1,176 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Browser is a named profile which configures how the browser is started or
+// connected. Attributes which are not set are left to the defaults of the
+// allocator, and flags given on the command line take precedence.
+type Browser struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+
+	ExecPath         *string
+	RemoteURL        *string
+	Env              map[string]string
+	Flags            map[string]any
+	WindowSize       []int
+	WSURLReadTimeout *time.Duration
+
+	UserDataDir      *string
+	ProxyServer      *string
+	UserAgent        *string
+	Headless         *bool
+	NoSandbox        *bool
+	DisableGPU       *bool
+	IgnoreCertErrors *bool
+	NoModifyURL      *bool
+}
+
+// DefaultBrowserProfile is the name of the browser profile used when no
+// profile is selected
+const DefaultBrowserProfile = "default"
+
+var (
+	browserBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "exec_path"},
+			{Name: "remote_url"},
+			{Name: "env"},
+			{Name: "flags"},
+			{Name: "window_size"},
+			{Name: "ws_url_timeout"},
+			{Name: "user_data_dir"},
+			{Name: "proxy_server"},
+			{Name: "user_agent"},
+			{Name: "headless"},
+			{Name: "no_sandbox"},
+			{Name: "disable_gpu"},
+			{Name: "ignore_cert_errors"},
+			{Name: "no_modify_url"},
+		},
+	}
+)
+
+func decodeBrowserBlock(block *hcl.Block) (*Browser, hcl.Diagnostics) {
+	b := new(Browser)
+	return reduce(
+		b,
+		block,
+		supportsDeclRange(&b.DeclRange),
+		supportsOptionalLabel(&b.Name, &b.NameRange),
+		supportsPartialContentSchema(
+			browserBlockSchema,
+			withAttribute("exec_path", &b.ExecPath),
+			withAttribute("remote_url", &b.RemoteURL),
+			withAttribute("env", &b.Env),
+			withAttr("flags", decodeBrowserFlags(&b.Flags)),
+			withAttr("window_size", decodeWindowSize(&b.WindowSize)),
+			withAttributeParser("ws_url_timeout", func(d time.Duration) {
+				b.WSURLReadTimeout = &d
+			}, time.ParseDuration),
+			withAttribute("user_data_dir", &b.UserDataDir),
+			withAttribute("proxy_server", &b.ProxyServer),
+			withAttribute("user_agent", &b.UserAgent),
+			withAttribute("headless", &b.Headless),
+			withAttribute("no_sandbox", &b.NoSandbox),
+			withAttribute("disable_gpu", &b.DisableGPU),
+			withAttribute("ignore_cert_errors", &b.IgnoreCertErrors),
+			withAttribute("no_modify_url", &b.NoModifyURL),
+		),
+	)
+}
+
+// decodeBrowserFlags decodes an object whose values are either bool, which
+// enables or suppresses the flag, or otherwise converted to string
+func decodeBrowserFlags(flags *map[string]any) func(*hcl.Attribute) hcl.Diagnostics {
+	return func(attr *hcl.Attribute) hcl.Diagnostics {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return diags
+		}
+		if !value.Type().IsObjectType() && !value.Type().IsMapType() {
+			return hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid flags",
+					Detail:   "The flags must be an object which maps the name of each flag to its value.",
+					Subject:  attr.Expr.Range().Ptr(),
+				},
+			}
+		}
+
+		res := map[string]any{}
+		for name, v := range value.AsValueMap() {
+			if v.Type() == cty.Bool && v.IsKnown() && !v.IsNull() {
+				res[name] = v.True()
+				continue
+			}
+			str, err := convert.Convert(v, cty.String)
+			if err != nil || str.IsNull() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid flags",
+					Detail:   fmt.Sprintf("The value of flag %q must be a bool or a string.", name),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			res[name] = str.AsString()
+		}
+		*flags = res
+		return diags
+	}
+}
+
+func decodeWindowSize(size *[]int) func(*hcl.Attribute) hcl.Diagnostics {
+	return func(attr *hcl.Attribute) hcl.Diagnostics {
+		var res []int
+		diags := gohcl.DecodeExpression(attr.Expr, nil, &res)
+		if diags.HasErrors() {
+			return diags
+		}
+		if len(res) != 2 || res[0] <= 0 || res[1] <= 0 {
+			return hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid window size",
+					Detail:   "The window size must be given as [WIDTH, HEIGHT] using positive numbers.",
+					Subject:  attr.Expr.Range().Ptr(),
+				},
+			}
+		}
+		*size = res
+		return nil
+	}
+}
+
+func checkDuplicateBrowsers(browsers []*Browser) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	seen := map[string]*Browser{}
+	for _, b := range browsers {
+		if prev, ok := seen[b.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate browser profile",
+				Detail:   fmt.Sprintf("The browser profile %q was already declared at %s.", b.Name, prev.DeclRange),
+				Subject:  &b.DeclRange,
+			})
+			continue
+		}
+		seen[b.Name] = b
+	}
+	return diags
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// If runs its tasks when the condition is true, otherwise the tasks in its
+// else block. A task block that has a when attribute is decoded as an If
+// which contains only that task.
+type If struct {
+	DeclRange hcl.Range
+	Condition hcl.Expression
+	Tasks     []Task
+	Else      []Task
+}
+
+// ForEach runs its tasks once for each item of a list or map, or once for each
+// element matched by its selectors. The values captured by each iteration are
+// collected into a list stored under its name.
+type ForEach struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Items     hcl.Expression
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+	Tasks     []Task
+}
+
+// Timeout limits the time its task can take. A task block that has a timeout
+// attribute is decoded as a Timeout which contains that task.
+type Timeout struct {
+	DeclRange hcl.Range
+	BlockType string
+	Duration  time.Duration
+	Task      Task
+}
+
+// Try runs its tasks and handles any error they produce by running the tasks
+// in its catch block. The tasks in its finally block always run.
+type Try struct {
+	DeclRange hcl.Range
+	Tasks     []Task
+	Catch     []Task
+	Finally   []Task
+}
+
+// Retry runs its tasks again when they fail, up to the given number of
+// attempts. The delay between attempts starts at Backoff and is scaled by
+// Multiplier after each attempt.
+type Retry struct {
+	DeclRange  hcl.Range
+	Attempts   int
+	Backoff    time.Duration
+	Multiplier float64
+	Tasks      []Task
+}
+
+var (
+	// taskBlocksSchema contains the blocks that can appear in a block which
+	// contains tasks
+	taskBlocksSchema = &hcl.BodySchema{
+		Blocks: automationBlockSchema.Blocks,
+	}
+
+	ifBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "condition", Required: true},
+		},
+		Blocks: slices.Concat(automationBlockSchema.Blocks, []hcl.BlockHeaderSchema{
+			{Type: "else"},
+		}),
+	}
+
+	forEachBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "items"},
+			{Name: "selector"},
+		},
+		Blocks: slices.Concat(automationBlockSchema.Blocks, []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		}),
+	}
+
+	tryBlockSchema = &hcl.BodySchema{
+		Blocks: slices.Concat(automationBlockSchema.Blocks, []hcl.BlockHeaderSchema{
+			{Type: "catch"},
+			{Type: "finally"},
+		}),
+	}
+
+	retryBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "attempts"},
+			{Name: "backoff"},
+			{Name: "multiplier"},
+		},
+		Blocks: automationBlockSchema.Blocks,
+	}
+
+	// taskAttributesSchema contains the attributes which are allowed on
+	// any task block
+	taskAttributesSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "when"},
+			{Name: "timeout"},
+		},
+	}
+)
+
+func init() {
+	// Blocks which contain tasks are registered here to prevent an
+	// initialization cycle with mappingTaskBlocks
+	mappingTaskBlocks["if"] = taskMapping(decodeIfBlock)
+	mappingTaskBlocks["for_each"] = taskMapping(decodeForEachBlock)
+	mappingTaskBlocks["try"] = taskMapping(decodeTryBlock)
+	mappingTaskBlocks["retry"] = taskMapping(decodeRetryBlock)
+}
+
+func decodeIfBlock(block *hcl.Block) (*If, hcl.Diagnostics) {
+	f := new(If)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsPartialContentSchema(
+			ifBlockSchema,
+			withAttributeExpression("condition", &f.Condition),
+			appendsTo(&f.Tasks, mappingTaskBlocks),
+			withBlock("else", func(b *hcl.Block) hcl.Diagnostics {
+				return supportsPartialContentSchema(
+					taskBlocksSchema,
+					appendsTo(&f.Else, mappingTaskBlocks),
+				)(b)
+			}),
+		),
+	)
+}
+
+func decodeForEachBlock(block *hcl.Block) (*ForEach, hcl.Diagnostics) {
+	f := new(ForEach)
+	res, diags := reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			forEachBlockSchema,
+			withAttributeExpression("items", &f.Items),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+			appendsTo(&f.Tasks, mappingTaskBlocks),
+		),
+	)
+
+	hasSelector := f.Selector != nil || len(f.Selectors) > 0
+	if (f.Items == nil) == !hasSelector {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid for_each block",
+			Detail:   "Exactly one of items or selector must be specified.",
+			Subject:  &block.DefRange,
+		})
+	}
+	return res, diags
+}
+
+func decodeTryBlock(block *hcl.Block) (*Try, hcl.Diagnostics) {
+	t := new(Try)
+	return reduceTask(
+		t,
+		block,
+		supportsDeclRange(&t.DeclRange),
+		supportsPartialContentSchema(
+			tryBlockSchema,
+			appendsTo(&t.Tasks, mappingTaskBlocks),
+			withBlock("catch", func(b *hcl.Block) hcl.Diagnostics {
+				return supportsPartialContentSchema(
+					taskBlocksSchema,
+					appendsTo(&t.Catch, mappingTaskBlocks),
+				)(b)
+			}),
+			withBlock("finally", func(b *hcl.Block) hcl.Diagnostics {
+				return supportsPartialContentSchema(
+					taskBlocksSchema,
+					appendsTo(&t.Finally, mappingTaskBlocks),
+				)(b)
+			}),
+		),
+	)
+}
+
+func decodeRetryBlock(block *hcl.Block) (*Retry, hcl.Diagnostics) {
+	r := &Retry{
+		Attempts:   3,
+		Multiplier: 1,
+	}
+	res, diags := reduceTask(
+		r,
+		block,
+		supportsDeclRange(&r.DeclRange),
+		supportsPartialContentSchema(
+			retryBlockSchema,
+			withAttribute("attempts", &r.Attempts),
+			withAttributeParser("backoff", r.setBackoff, time.ParseDuration),
+			withAttribute("multiplier", &r.Multiplier),
+			appendsTo(&r.Tasks, mappingTaskBlocks),
+		),
+	)
+
+	if r.Attempts < 1 || r.Multiplier < 1 || r.Backoff < 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid retry block",
+			Detail:   "Attempts and multiplier must be at least 1, and backoff must not be negative.",
+			Subject:  &block.DefRange,
+		})
+	}
+	return res, diags
+}
+
+// decodeTaskAttributes applies the attributes which are allowed on any task
+// block, which can wrap the task that was decoded.
+func decodeTaskAttributes(block *hcl.Block, task Task) (Task, hcl.Diagnostics) {
+	content, _, diags := block.Body.PartialContent(taskAttributesSchema)
+	if _, ok := content.Attributes["timeout"]; ok {
+		t := &Timeout{
+			DeclRange: block.DefRange,
+			BlockType: block.Type,
+			Task:      task,
+		}
+		diags = append(diags, withAttributeParser("timeout", t.setDuration, time.ParseDuration)(content)...)
+		task = t
+	}
+	if when, ok := content.Attributes["when"]; ok {
+		task = &If{
+			DeclRange: block.DefRange,
+			Condition: when.Expr,
+			Tasks:     []Task{task},
+		}
+	}
+	return task, diags
+}
+
+func (r *Retry) setBackoff(d time.Duration) {
+	r.Backoff = d
+}
+
+func (t *Timeout) setDuration(d time.Duration) {
+	t.Duration = d
+}
+
+func (*ForEach) taskSigil() {}
+func (*If) taskSigil()      {}
+func (*Retry) taskSigil()   {}
+func (*Timeout) taskSigil() {}
+func (*Try) taskSigil()     {}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Device describes a custom device for emulation, which is identified by
+// its name and can be selected in the same manner as the built-in devices.
+type Device struct {
+	DeclRange   hcl.Range
+	NameRange   hcl.Range
+	Name        string
+	Description string
+	UserAgent   string
+	Width       int64
+	Height      int64
+	Scale       float64
+	Landscape   bool
+	Mobile      bool
+	Touch       bool
+}
+
+var (
+	deviceBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "description"},
+			{Name: "user_agent"},
+			{Name: "width", Required: true},
+			{Name: "height", Required: true},
+			{Name: "scale"},
+			{Name: "landscape"},
+			{Name: "mobile"},
+			{Name: "touch"},
+		},
+	}
+)
+
+func decodeDeviceBlock(block *hcl.Block) (*Device, hcl.Diagnostics) {
+	d := &Device{
+		Scale: 1,
+	}
+	res, diags := reduce(
+		d,
+		block,
+		supportsDeclRange(&d.DeclRange),
+		supportsOptionalLabel(&d.Name, &d.NameRange),
+		supportsPartialContentSchema(
+			deviceBlockSchema,
+			withAttribute("description", &d.Description),
+			withAttribute("user_agent", &d.UserAgent),
+			withAttribute("width", &d.Width),
+			withAttribute("height", &d.Height),
+			withAttribute("scale", &d.Scale),
+			withAttribute("landscape", &d.Landscape),
+			withAttribute("mobile", &d.Mobile),
+			withAttribute("touch", &d.Touch),
+		),
+	)
+	if diags.HasErrors() {
+		return res, diags
+	}
+
+	if d.Width <= 0 || d.Height <= 0 || d.Scale <= 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid device dimensions",
+			Detail:   fmt.Sprintf("The width, height and scale of device %q must be positive.", d.Name),
+			Subject:  &d.DeclRange,
+		})
+	}
+	return res, diags
+}
+
+func checkDuplicateDevices(devices []*Device) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	seen := map[string]*Device{}
+	for _, d := range devices {
+		if prev, ok := seen[d.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate device",
+				Detail:   fmt.Sprintf("The device %q was already declared at %s.", d.Name, prev.DeclRange),
+				Subject:  &d.DeclRange,
+			})
+			continue
+		}
+		seen[d.Name] = d
+	}
+	return diags
+}
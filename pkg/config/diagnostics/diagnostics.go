@@ -0,0 +1,78 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package diagnostics writes the diagnostics produced when checking files in
+// formats which are suitable for people and for tools.
+package diagnostics
+
+import (
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Writer collects diagnostics and writes them in a particular format. Formats
+// which produce a single document only write output when flushed.
+type Writer interface {
+	WriteDiagnostics(hcl.Diagnostics) error
+	Flush() error
+}
+
+// NewTextWriter creates a writer which writes diagnostics as text, including
+// the source snippets of the files.
+func NewTextWriter(w io.Writer, files map[string]*hcl.File, width uint, color bool) Writer {
+	return textWriter{hcl.NewDiagnosticTextWriter(w, files, width, color)}
+}
+
+type textWriter struct {
+	hcl.DiagnosticWriter
+}
+
+func (textWriter) Flush() error {
+	return nil
+}
+
+// collector accumulates the diagnostics for formats which write a document
+type collector struct {
+	diags hcl.Diagnostics
+}
+
+func (c *collector) WriteDiagnostics(diags hcl.Diagnostics) error {
+	c.diags = append(c.diags, diags...)
+	return nil
+}
+
+func severityName(s hcl.DiagnosticSeverity) string {
+	switch s {
+	case hcl.DiagError:
+		return "error"
+	case hcl.DiagWarning:
+		return "warning"
+	}
+	return "invalid"
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ruleID derives a stable identifier for the kind of diagnostic from its
+// summary, omitting quoted names so that similar diagnostics share it. The
+// extra information of the diagnostic can provide the identifier instead
+// using a Rule method.
+func ruleID(d *hcl.Diagnostic) string {
+	if r, ok := d.Extra.(interface{ Rule() string }); ok {
+		return r.Rule()
+	}
+	summary := strings.ToLower(d.Summary)
+	if i := strings.IndexByte(summary, '"'); i >= 0 {
+		summary = summary[:i]
+	}
+	return strings.Trim(nonWord.ReplaceAllString(summary, "-"), "-")
+}
+
+func uri(filename string) string {
+	return filepath.ToSlash(filename)
+}
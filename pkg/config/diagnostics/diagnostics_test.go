@@ -0,0 +1,113 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diagnostics_test
+
+import (
+	"bytes"
+
+	"github.com/Carbonfrost/autogun/pkg/config/diagnostics"
+	"github.com/hashicorp/hcl/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Writer", func() {
+
+	var diags = hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  `Duplicate selector`,
+			Detail:   "The selector selector.login was already declared.",
+			Subject: &hcl.Range{
+				Filename: "site.autog",
+				Start:    hcl.Pos{Line: 3, Column: 1, Byte: 20},
+				End:      hcl.Pos{Line: 3, Column: 17, Byte: 36},
+			},
+		},
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  `Invalid identifier name "2fa"`,
+		},
+	}
+
+	Describe("NewJSONWriter", func() {
+
+		It("writes the diagnostics and counts", func() {
+			var buf bytes.Buffer
+			w := diagnostics.NewJSONWriter(&buf)
+			Expect(w.WriteDiagnostics(diags)).To(Succeed())
+			Expect(buf.Len()).To(BeZero())
+
+			Expect(w.Flush()).To(Succeed())
+			Expect(buf.String()).To(MatchJSON(`{
+				"valid": false,
+				"error_count": 1,
+				"warning_count": 1,
+				"diagnostics": [
+					{
+						"severity": "error",
+						"summary": "Duplicate selector",
+						"detail": "The selector selector.login was already declared.",
+						"range": {
+							"filename": "site.autog",
+							"start": {"line": 3, "column": 1, "byte": 20},
+							"end": {"line": 3, "column": 17, "byte": 36}
+						}
+					},
+					{
+						"severity": "warning",
+						"summary": "Invalid identifier name \"2fa\""
+					}
+				]
+			}`))
+		})
+
+		It("writes an empty list when there are no diagnostics", func() {
+			var buf bytes.Buffer
+			Expect(diagnostics.NewJSONWriter(&buf).Flush()).To(Succeed())
+			Expect(buf.String()).To(MatchJSON(`{"valid": true, "error_count": 0, "warning_count": 0, "diagnostics": []}`))
+		})
+	})
+
+	Describe("NewSARIFWriter", func() {
+
+		It("writes the diagnostics as results", func() {
+			var buf bytes.Buffer
+			w := diagnostics.NewSARIFWriter(&buf, diagnostics.Tool{Name: "autogun", Version: "1.0.0"})
+			Expect(w.WriteDiagnostics(diags)).To(Succeed())
+			Expect(w.Flush()).To(Succeed())
+			Expect(buf.String()).To(MatchJSON(`{
+				"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+				"version": "2.1.0",
+				"runs": [
+					{
+						"tool": {"driver": {"name": "autogun", "version": "1.0.0"}},
+						"results": [
+							{
+								"ruleId": "duplicate-selector",
+								"level": "error",
+								"message": {"text": "Duplicate selector: The selector selector.login was already declared."},
+								"locations": [
+									{
+										"physicalLocation": {
+											"artifactLocation": {"uri": "site.autog"},
+											"region": {"startLine": 3, "startColumn": 1, "endLine": 3, "endColumn": 17}
+										}
+									}
+								]
+							},
+							{
+								"ruleId": "invalid-identifier-name",
+								"level": "warning",
+								"message": {"text": "Invalid identifier name \"2fa\""}
+							}
+						]
+					}
+				]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// NewJSONWriter creates a writer which writes a JSON document that contains
+// the diagnostics and the number of errors and warnings.
+func NewJSONWriter(w io.Writer) Writer {
+	return &jsonWriter{w: w}
+}
+
+type jsonWriter struct {
+	collector
+	w io.Writer
+}
+
+type jsonDocument struct {
+	Valid        bool              `json:"valid"`
+	ErrorCount   int               `json:"error_count"`
+	WarningCount int               `json:"warning_count"`
+	Diagnostics  []*jsonDiagnostic `json:"diagnostics"`
+}
+
+type jsonDiagnostic struct {
+	Severity string     `json:"severity"`
+	Summary  string     `json:"summary"`
+	Detail   string     `json:"detail,omitempty"`
+	Range    *jsonRange `json:"range,omitempty"`
+}
+
+type jsonRange struct {
+	Filename string  `json:"filename"`
+	Start    jsonPos `json:"start"`
+	End      jsonPos `json:"end"`
+}
+
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+func (j *jsonWriter) Flush() error {
+	doc := jsonDocument{
+		Diagnostics: make([]*jsonDiagnostic, 0, len(j.diags)),
+	}
+	for _, d := range j.diags {
+		switch d.Severity {
+		case hcl.DiagError:
+			doc.ErrorCount++
+		case hcl.DiagWarning:
+			doc.WarningCount++
+		}
+
+		jd := &jsonDiagnostic{
+			Severity: severityName(d.Severity),
+			Summary:  d.Summary,
+			Detail:   d.Detail,
+		}
+		if d.Subject != nil {
+			jd.Range = &jsonRange{
+				Filename: uri(d.Subject.Filename),
+				Start:    jsonPos{d.Subject.Start.Line, d.Subject.Start.Column, d.Subject.Start.Byte},
+				End:      jsonPos{d.Subject.End.Line, d.Subject.End.Column, d.Subject.End.Byte},
+			}
+		}
+		doc.Diagnostics = append(doc.Diagnostics, jd)
+	}
+	doc.Valid = doc.ErrorCount == 0
+
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// Tool describes the tool which produced the diagnostics
+type Tool struct {
+	Name           string
+	Version        string
+	InformationURI string
+}
+
+// NewSARIFWriter creates a writer which writes a SARIF 2.1.0 log that
+// contains a run of the tool, whose results are the diagnostics.
+func NewSARIFWriter(w io.Writer, tool Tool) Writer {
+	return &sarifWriter{w: w, tool: tool}
+}
+
+type sarifWriter struct {
+	collector
+	w    io.Writer
+	tool Tool
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+func (s *sarifWriter) Flush() error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           s.tool.Name,
+				Version:        s.tool.Version,
+				InformationURI: s.tool.InformationURI,
+			},
+		},
+		Results: make([]*sarifResult, 0, len(s.diags)),
+	}
+
+	for _, d := range s.diags {
+		text := d.Summary
+		if d.Detail != "" {
+			text += ": " + d.Detail
+		}
+		level := "error"
+		if d.Severity == hcl.DiagWarning {
+			level = "warning"
+		}
+
+		res := &sarifResult{
+			RuleID:  ruleID(d),
+			Level:   level,
+			Message: sarifMessage{Text: text},
+		}
+		if d.Subject != nil {
+			res.Locations = []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: uri(d.Subject.Filename)},
+						Region: sarifRegion{
+							StartLine:   d.Subject.Start.Line,
+							StartColumn: d.Subject.Start.Column,
+							EndLine:     d.Subject.End.Line,
+							EndColumn:   d.Subject.End.Column,
+						},
+					},
+				},
+			}
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	enc := json.NewEncoder(s.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
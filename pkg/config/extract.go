@@ -0,0 +1,173 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Extract captures a list of objects, one for each element matched by its
+// root selectors, whose attributes are the values of its fields. When next
+// is specified, the element it matches is clicked to load more pages, up to
+// MaxPages.
+type Extract struct {
+	DeclRange     hcl.Range
+	NameRange     hcl.Range
+	Name          string
+	Root          hcl.Expression
+	Selectors     []*Selector
+	Next          hcl.Expression
+	NextSelectors []*Selector
+	MaxPages      int
+	Fields        []*ExtractField
+	Options       *Options
+}
+
+// ExtractField is a field of the objects captured by extract. Its value is
+// the text of the first element matched by its selectors within the root
+// element, or the value of the attribute when one is specified. The text is
+// converted to the type of the field, which defaults to string.
+type ExtractField struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Attribute string
+	Type      cty.Type
+}
+
+var (
+	extractBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "root", Required: true},
+			{Name: "next"},
+			{Name: "max_pages"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       "field",
+				LabelNames: []string{"name"},
+			},
+			{Type: "options"},
+		},
+	}
+
+	fieldBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+			{Name: "attribute"},
+			{Name: "type"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+		},
+	}
+)
+
+func decodeExtractBlock(block *hcl.Block) (*Extract, hcl.Diagnostics) {
+	f := &Extract{
+		MaxPages: 10,
+	}
+	res, diags := reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			extractBlockSchema,
+			withSelectorRefAttribute("root", &f.Root, &f.Selectors),
+			withSelectorRefAttribute("next", &f.Next, &f.NextSelectors),
+			withAttribute("max_pages", &f.MaxPages),
+			withBlock("field", func(b *hcl.Block) hcl.Diagnostics {
+				field, fieldDiags := decodeFieldBlock(b)
+				f.Fields = append(f.Fields, field)
+				return fieldDiags
+			}),
+			withBlock("options", func(b *hcl.Block) hcl.Diagnostics {
+				opts, optsDiags := decodeOptionsBlock(b)
+				f.Options = opts
+				return optsDiags
+			}),
+		),
+	)
+
+	if f.MaxPages < 1 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid extract block",
+			Detail:   "The max_pages must be at least 1.",
+			Subject:  &block.DefRange,
+		})
+	}
+	diags = append(diags, checkDuplicateFields(f.Fields)...)
+	return res, diags
+}
+
+func decodeFieldBlock(block *hcl.Block) (*ExtractField, hcl.Diagnostics) {
+	f := &ExtractField{
+		Type: cty.String,
+	}
+	res, diags := reduce(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			fieldBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			withAttribute("attribute", &f.Attribute),
+			withAttributeType("type", &f.Type),
+			withBlock("selector", func(b *hcl.Block) hcl.Diagnostics {
+				sel, selDiags := decodeSelectorBlock(b)
+				if sel != nil {
+					f.Selectors = append(f.Selectors, sel)
+				}
+				return selDiags
+			}),
+		),
+	)
+
+	if f.Selector == nil && len(f.Selectors) == 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid field block",
+			Detail:   "A selector must be specified to extract a field.",
+			Subject:  &block.DefRange,
+		})
+	}
+	if !f.Type.IsPrimitiveType() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid field type",
+			Detail:   "The type of a field must be string, number or bool.",
+			Subject:  &block.DefRange,
+		})
+	}
+	return res, diags
+}
+
+func checkDuplicateFields(fields []*ExtractField) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	seen := map[string]*ExtractField{}
+	for _, f := range fields {
+		if prev, ok := seen[f.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate field",
+				Detail:   fmt.Sprintf("The field %q was already declared at %s.", f.Name, prev.DeclRange),
+				Subject:  &f.DeclRange,
+			})
+			continue
+		}
+		seen[f.Name] = f
+	}
+	return diags
+}
+
+func (*Extract) taskSigil() {}
@@ -10,7 +10,24 @@ import (
 
 type File struct {
 	Automations []*Automation
-	filename    string
+	Variables   []*Variable
+	Locals      []*Local
+	Imports     []*Import
+	Selectors   []*Selector
+	Pages       []*Page
+	Browsers    []*Browser
+	Devices     []*Device
+
+	// Lint configures the rules of the linter, which is nil when the file
+	// does not contain a lint block
+	Lint *Lint
+
+	// Format configures the style applied by fmt, which is nil when the
+	// file does not contain a format block
+	Format *Format
+
+	filename string
+	pkg      string
 }
 
 func (f *File) Name() string {
@@ -21,6 +38,16 @@ func (f *File) SetName(s string) {
 	f.filename = s
 }
 
+// Package gets the name of the package which contains the file, which is
+// empty for files of the workspace itself
+func (f *File) Package() string {
+	return f.pkg
+}
+
+func (f *File) SetPackage(s string) {
+	f.pkg = s
+}
+
 var (
 	fileSchema = &hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{},
@@ -29,6 +56,39 @@ var (
 				Type:       "automation",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "variable",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "locals",
+			},
+			{
+				Type:       "import",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "selector",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "page",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "browser",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "device",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "lint",
+			},
+			{
+				Type: "format",
+			},
 		},
 	}
 )
@@ -52,10 +112,78 @@ func decodeFile(filename string, body hcl.Body) (*File, hcl.Diagnostics) {
 				f.Automations = append(f.Automations, cfg)
 			}
 
+		case "variable":
+			cfg, cfgDiags := decodeVariableBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Variables = append(f.Variables, cfg)
+			}
+
+		case "locals":
+			cfg, cfgDiags := decodeLocalsBlock(block)
+			diags = append(diags, cfgDiags...)
+			f.Locals = append(f.Locals, cfg...)
+
+		case "import":
+			cfg, cfgDiags := decodeImportBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Imports = append(f.Imports, cfg)
+			}
+
+		case "selector":
+			cfg, cfgDiags := decodeSelectorBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Selectors = append(f.Selectors, cfg)
+			}
+
+		case "page":
+			cfg, cfgDiags := decodePageBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Pages = append(f.Pages, cfg)
+			}
+
+		case "browser":
+			cfg, cfgDiags := decodeBrowserBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Browsers = append(f.Browsers, cfg)
+			}
+
+		case "device":
+			cfg, cfgDiags := decodeDeviceBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				f.Devices = append(f.Devices, cfg)
+			}
+
+		case "lint":
+			cfg, cfgDiags := decodeLintBlock(block)
+			diags = append(diags, cfgDiags...)
+			if f.Lint != nil {
+				diags = append(diags, checkDuplicateLint(f.Lint, cfg)...)
+				continue
+			}
+			f.Lint = cfg
+
+		case "format":
+			cfg, cfgDiags := decodeFormatBlock(block)
+			diags = append(diags, cfgDiags...)
+			if f.Format != nil {
+				diags = append(diags, checkDuplicateFormat(f.Format, cfg)...)
+				continue
+			}
+			f.Format = cfg
+
 		default:
 			continue
 		}
 	}
 
+	diags = append(diags, checkDuplicateVariables(f.Variables)...)
+	diags = append(diags, checkDuplicateBrowsers(f.Browsers)...)
+	diags = append(diags, checkDuplicateDevices(f.Devices)...)
 	return f, diags
 }
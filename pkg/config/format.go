@@ -0,0 +1,111 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Format configures the canonical style which is applied by fmt. Settings
+// which are not specified keep their default.
+type Format struct {
+	DeclRange     hcl.Range
+	EnumCase      EnumCase
+	SelectorStyle SelectorStyle
+}
+
+// EnumCase is the casing of the values of by and on attributes, which can be
+// written in either upper or lower case
+type EnumCase string
+
+// SelectorStyle is how the selector of a task is written when it has only a
+// target. Unless a style is specified, selectors are kept as written.
+type SelectorStyle string
+
+const (
+	EnumCaseUpper EnumCase = "UPPER"
+	EnumCaseLower EnumCase = "LOWER"
+)
+
+const (
+	SelectorStylePreserve  SelectorStyle = ""
+	SelectorStyleAttribute SelectorStyle = "ATTRIBUTE"
+	SelectorStyleBlock     SelectorStyle = "BLOCK"
+)
+
+var (
+	formatBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "enum_case"},
+			{Name: "selector_style"},
+		},
+	}
+)
+
+// DefaultFormat gets the settings which are used when no format block is
+// declared
+func DefaultFormat() *Format {
+	return &Format{
+		EnumCase:      EnumCaseUpper,
+		SelectorStyle: SelectorStylePreserve,
+	}
+}
+
+func (f *Format) setEnumCase(c EnumCase) {
+	f.EnumCase = c
+}
+
+func (f *Format) setSelectorStyle(s SelectorStyle) {
+	f.SelectorStyle = s
+}
+
+func decodeFormatBlock(block *hcl.Block) (*Format, hcl.Diagnostics) {
+	f := DefaultFormat()
+	return reduce(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsPartialContentSchema(
+			formatBlockSchema,
+			withAttributeParser("enum_case", f.setEnumCase, parseEnumCase),
+			withAttributeParser("selector_style", f.setSelectorStyle, parseSelectorStyle),
+		),
+	)
+}
+
+func checkDuplicateFormat(prev, f *Format) hcl.Diagnostics {
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Duplicate format block",
+			Detail:   fmt.Sprintf("The format block was already declared at %s.", prev.DeclRange),
+			Subject:  &f.DeclRange,
+		},
+	}
+}
+
+func parseEnumCase(s string) (result EnumCase, err error) {
+	switch s {
+	case "UPPER", "upper":
+		return EnumCaseUpper, nil
+	case "LOWER", "lower":
+		return EnumCaseLower, nil
+	}
+	err = fmt.Errorf("value %q is not a valid value", s)
+	return
+}
+
+func parseSelectorStyle(s string) (result SelectorStyle, err error) {
+	switch s {
+	case "ATTRIBUTE", "attribute":
+		return SelectorStyleAttribute, nil
+	case "BLOCK", "block":
+		return SelectorStyleBlock, nil
+	}
+	err = fmt.Errorf("value %q is not a valid value", s)
+	return
+}
@@ -0,0 +1,306 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// rewriter collects edits to the source of a file, which are applied at once
+type rewriter struct {
+	src      []byte
+	comments []hclsyntax.Token
+	edits    []edit
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// chunk is the source of an attribute together with the comments on the
+// lines directly above it
+type chunk struct {
+	start, end int
+	rank       int
+}
+
+// Canonical applies source formatting to a file in the native syntax and
+// rewrites it in the canonical style. The values of by and on attributes
+// are cased, selectors which have only a target are written in the
+// preferred style when one is specified, and attributes on adjacent lines
+// are ordered as their block declares them. When settings is nil, the
+// defaults are used.
+func Canonical(filename string, src []byte, settings *config.Format) ([]byte, hcl.Diagnostics) {
+	if settings == nil {
+		settings = config.DefaultFormat()
+	}
+
+	src, diags := rewrite(filename, src, func(r *rewriter, body *hclsyntax.Body, path []string) {
+		r.enumCase(body, path, settings.EnumCase)
+		r.selectorStyle(body, path, settings.SelectorStyle)
+	})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Attributes are ordered separately because moving them could overlap
+	// with the edits above
+	src, diags = rewrite(filename, src, (*rewriter).sortAttributes)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return Source(src), nil
+}
+
+// File formats a file in the native syntax. It is rewritten in the canonical
+// style unless it has syntax errors, which [Canonical] cannot rewrite. Then
+// only source formatting is applied, as by [Source].
+func File(filename string, src []byte, settings *config.Format) []byte {
+	out, diags := Canonical(filename, src, settings)
+	if diags.HasErrors() {
+		return Source(src)
+	}
+	return out
+}
+
+func rewrite(filename string, src []byte, visit func(*rewriter, *hclsyntax.Body, []string)) ([]byte, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	r := &rewriter{src: src}
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	for _, tok := range tokens {
+		if tok.Type == hclsyntax.TokenComment {
+			r.comments = append(r.comments, tok)
+		}
+	}
+
+	r.walk(file.Body.(*hclsyntax.Body), nil, visit)
+	return r.apply(), nil
+}
+
+func (r *rewriter) walk(body *hclsyntax.Body, path []string, visit func(*rewriter, *hclsyntax.Body, []string)) {
+	visit(r, body, path)
+	for _, b := range body.Blocks {
+		r.walk(b.Body, slices.Concat(path, []string{b.Type}), visit)
+	}
+}
+
+func (r *rewriter) replace(rng hcl.Range, text string) {
+	r.edits = append(r.edits, edit{rng.Start.Byte, rng.End.Byte, text})
+}
+
+func (r *rewriter) apply() []byte {
+	slices.SortFunc(r.edits, func(x, y edit) int {
+		return y.start - x.start
+	})
+
+	out := slices.Clone(r.src)
+	for _, e := range r.edits {
+		out = slices.Concat(out[:e.start], []byte(e.text), out[e.end:])
+	}
+	return out
+}
+
+func (r *rewriter) text(rng hcl.Range) string {
+	return string(rng.SliceBytes(r.src))
+}
+
+func (r *rewriter) hasComments(rng hcl.Range) bool {
+	return slices.ContainsFunc(r.comments, func(tok hclsyntax.Token) bool {
+		return tok.Range.Start.Byte >= rng.Start.Byte && tok.Range.Start.Byte < rng.End.Byte
+	})
+}
+
+// enumCase cases the values of the by and on attributes of selectors.
+// Values which are not constant or not valid are left as they are.
+func (r *rewriter) enumCase(body *hclsyntax.Body, path []string, c config.EnumCase) {
+	if len(path) == 0 || path[len(path)-1] != "selector" {
+		return
+	}
+
+	for name, values := range map[string][]string{
+		"by": enumValues(config.SelectorByValues),
+		"on": enumValues(config.SelectorOnValues),
+	} {
+		attr, ok := body.Attributes[name]
+		if !ok {
+			continue
+		}
+		v, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() || v.Type() != cty.String || !v.IsKnown() || v.IsNull() {
+			continue
+		}
+
+		s := v.AsString()
+		if !slices.ContainsFunc(values, func(value string) bool { return strings.EqualFold(value, s) }) {
+			continue
+		}
+
+		cased := strings.ToUpper(s)
+		if c == config.EnumCaseLower {
+			cased = strings.ToLower(s)
+		}
+		if quoted := quoteString(cased); quoted != r.text(attr.Expr.Range()) {
+			r.replace(attr.Expr.Range(), quoted)
+		}
+	}
+}
+
+func enumValues[T ~string](values []T) []string {
+	res := make([]string, len(values))
+	for i, v := range values {
+		res[i] = string(v)
+	}
+	return res
+}
+
+// selectorStyle writes the selector of a task in the preferred style when it
+// has only a target. References to named selectors are always attributes.
+// Selectors are kept as written when no style is preferred.
+func (r *rewriter) selectorStyle(body *hclsyntax.Body, path []string, style config.SelectorStyle) {
+	schema := config.BlockSchema(path...)
+	if schema == nil ||
+		!slices.ContainsFunc(schema.Attributes, func(a hcl.AttributeSchema) bool { return a.Name == "selector" }) ||
+		!slices.ContainsFunc(schema.Blocks, func(b hcl.BlockHeaderSchema) bool { return b.Type == "selector" }) {
+		return
+	}
+
+	attr, hasAttr := body.Attributes["selector"]
+	var blocks []*hclsyntax.Block
+	for _, b := range body.Blocks {
+		if b.Type == "selector" {
+			blocks = append(blocks, b)
+		}
+	}
+
+	switch style {
+	case config.SelectorStyleAttribute:
+		if hasAttr || len(blocks) != 1 {
+			return
+		}
+		b := blocks[0]
+		target, ok := b.Body.Attributes["target"]
+		if !ok || len(b.Labels) > 0 || len(b.Body.Attributes) > 1 || len(b.Body.Blocks) > 0 || r.hasComments(b.Range()) {
+			return
+		}
+		text := r.text(target.Expr.Range())
+		if selectorRefPattern.MatchString(text) {
+			return
+		}
+		r.replace(b.Range(), "selector = "+text)
+
+	case config.SelectorStyleBlock:
+		if !hasAttr {
+			return
+		}
+		text := r.text(attr.Expr.Range())
+		if selectorRefPattern.MatchString(text) {
+			return
+		}
+		r.replace(attr.SrcRange, "selector {\ntarget = "+text+"\n}")
+	}
+}
+
+// sortAttributes orders attributes in the order which their block declares
+// them. Like imports in Go, only attributes on adjacent lines are ordered, so
+// blank lines separate groups which keep their order.
+func (r *rewriter) sortAttributes(body *hclsyntax.Body, path []string) {
+	schema := config.BlockSchema(path...)
+	if schema == nil {
+		return
+	}
+
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, a := range body.Attributes {
+		attrs = append(attrs, a)
+	}
+	slices.SortFunc(attrs, func(x, y *hclsyntax.Attribute) int {
+		return x.SrcRange.Start.Byte - y.SrcRange.Start.Byte
+	})
+
+	var group []chunk
+	flush := func() {
+		sorted := slices.SortedStableFunc(slices.Values(group), func(x, y chunk) int {
+			return x.rank - y.rank
+		})
+		if !slices.Equal(sorted, group) {
+			var text bytes.Buffer
+			for _, c := range sorted {
+				text.Write(r.src[c.start:c.end])
+				if r.src[c.end-1] != '\n' {
+					text.WriteByte('\n')
+				}
+			}
+			r.edits = append(r.edits, edit{group[0].start, group[len(group)-1].end, text.String()})
+		}
+		group = nil
+	}
+
+	for _, a := range attrs {
+		c, ok := r.chunk(a)
+		if !ok {
+			flush()
+			continue
+		}
+		c.rank = slices.IndexFunc(schema.Attributes, func(s hcl.AttributeSchema) bool {
+			return s.Name == a.Name
+		})
+		if c.rank < 0 {
+			c.rank = len(schema.Attributes)
+		}
+
+		if len(group) > 0 && group[len(group)-1].end != c.start {
+			flush()
+		}
+		group = append(group, c)
+	}
+	flush()
+}
+
+// chunk gets the lines of an attribute, which must not share its lines with
+// anything but a comment at the end
+func (r *rewriter) chunk(a *hclsyntax.Attribute) (chunk, bool) {
+	start := lineStart(r.src, a.SrcRange.Start.Byte)
+	if len(bytes.TrimSpace(r.src[start:a.SrcRange.Start.Byte])) > 0 {
+		return chunk{}, false
+	}
+
+	// Include the comments on the lines directly above
+	for start > 0 {
+		prev := lineStart(r.src, start-1)
+		indent := prev + len(r.src[prev:start]) - len(bytes.TrimLeft(r.src[prev:start], " \t"))
+		if !slices.ContainsFunc(r.comments, func(tok hclsyntax.Token) bool {
+			return tok.Range.Start.Byte == indent && tok.Range.End.Byte == start
+		}) {
+			break
+		}
+		start = prev
+	}
+
+	end := len(r.src)
+	if i := bytes.IndexByte(r.src[a.SrcRange.End.Byte:], '\n'); i >= 0 {
+		end = a.SrcRange.End.Byte + i + 1
+	}
+	rest := bytes.TrimSpace(r.src[a.SrcRange.End.Byte:end])
+	if len(rest) > 0 && !bytes.HasPrefix(rest, []byte("#")) && !bytes.HasPrefix(rest, []byte("//")) {
+		return chunk{}, false
+	}
+	return chunk{start: start, end: end}, true
+}
+
+func lineStart(src []byte, pos int) int {
+	return bytes.LastIndexByte(src[:pos], '\n') + 1
+}
@@ -0,0 +1,268 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/config/format"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func lines(s ...string) string {
+	return strings.Join(s, "\n") + "\n"
+}
+
+var _ = Describe("Canonical", func() {
+
+	DescribeTable("examples",
+		func(settings *config.Format, src string, expected string) {
+			out, diags := format.Canonical("a.autog", []byte(src), settings)
+			Expect(diags).To(BeEmpty())
+			Expect(string(out)).To(Equal(expected))
+		},
+		Entry("upper case enums",
+			nil,
+			lines(
+				`selector "a" {`,
+				`  target = "#a"`,
+				`  by = "query_all"`,
+				`  on = "visible"`,
+				`}`,
+			),
+			lines(
+				`selector "a" {`,
+				`  target = "#a"`,
+				`  by     = "QUERY_ALL"`,
+				`  on     = "VISIBLE"`,
+				`}`,
+			),
+		),
+		Entry("lower case enums",
+			&config.Format{EnumCase: config.EnumCaseLower, SelectorStyle: config.SelectorStyleAttribute},
+			lines(
+				`page "p" {`,
+				`  selector "a" {`,
+				`    target = "#a"`,
+				`    by     = "QUERY"`,
+				`  }`,
+				`}`,
+			),
+			lines(
+				`page "p" {`,
+				`  selector "a" {`,
+				`    target = "#a"`,
+				`    by     = "query"`,
+				`  }`,
+				`}`,
+			),
+		),
+		Entry("selectors kept as written by default",
+			nil,
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#a"`,
+				`    }`,
+				`  }`,
+				`  click {`,
+				`    selector = "#b"`,
+				`  }`,
+				`}`,
+			),
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#a"`,
+				`    }`,
+				`  }`,
+				`  click {`,
+				`    selector = "#b"`,
+				`  }`,
+				`}`,
+			),
+		),
+		Entry("selector blocks with only a target",
+			&config.Format{EnumCase: config.EnumCaseUpper, SelectorStyle: config.SelectorStyleAttribute},
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#a"`,
+				`    }`,
+				`  }`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#b"`,
+				`      by     = "QUERY"`,
+				`    }`,
+				`  }`,
+				`}`,
+			),
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector = "#a"`,
+				`  }`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#b"`,
+				`      by     = "QUERY"`,
+				`    }`,
+				`  }`,
+				`}`,
+			),
+		),
+		Entry("selector attributes",
+			&config.Format{EnumCase: config.EnumCaseUpper, SelectorStyle: config.SelectorStyleBlock},
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector = "#a"`,
+				`  }`,
+				`  click {`,
+				`    selector = selector.submit`,
+				`  }`,
+				`}`,
+			),
+			lines(
+				`automation "a" {`,
+				`  click {`,
+				`    selector {`,
+				`      target = "#a"`,
+				`    }`,
+				`  }`,
+				`  click {`,
+				`    selector = selector.submit`,
+				`  }`,
+				`}`,
+			),
+		),
+		Entry("attribute order",
+			nil,
+			lines(
+				`variable "v" {`,
+				`  # The description`,
+				`  description = "Value"`,
+				`  default     = 1 # One`,
+				`  type        = number`,
+				`}`,
+				`browser "b" {`,
+				`  headless  = true`,
+				`  exec_path = "chrome"`,
+				``,
+				`  user_agent = "x"`,
+				`}`,
+			),
+			lines(
+				`variable "v" {`,
+				`  type    = number`,
+				`  default = 1 # One`,
+				`  # The description`,
+				`  description = "Value"`,
+				`}`,
+				`browser "b" {`,
+				`  exec_path = "chrome"`,
+				`  headless  = true`,
+				``,
+				`  user_agent = "x"`,
+				`}`,
+			),
+		),
+	)
+
+	examples, _ := filepath.Glob("../testdata/valid-examples/*.autog")
+
+	for _, example := range examples {
+		It("rewrites "+filepath.Base(example)+" to a stable and valid file", func() {
+			src, err := os.ReadFile(example)
+			Expect(err).NotTo(HaveOccurred())
+
+			out, diags := format.Canonical(example, src, nil)
+			Expect(diags).To(BeEmpty())
+			_, diags = config.NewParser(nil).ParseSource(example, out)
+			Expect(diags).To(BeEmpty(), string(out))
+
+			again, _ := format.Canonical(example, out, nil)
+			Expect(string(again)).To(Equal(string(out)))
+		})
+	}
+
+	It("keeps the canonical style", func() {
+		src := lines(
+			`selector "a" {`,
+			`  target = "#a"`,
+			`  by     = "QUERY"`,
+			`}`,
+			`automation "a" {`,
+			`  click {`,
+			`    selector = "#a"`,
+			`    when     = true`,
+			`  }`,
+			`}`,
+		)
+		out, diags := format.Canonical("a.autog", []byte(src), nil)
+		Expect(diags).To(BeEmpty())
+		Expect(string(out)).To(Equal(src))
+	})
+
+	It("reports syntax errors", func() {
+		_, diags := format.Canonical("a.autog", []byte("automation {"), nil)
+		Expect(diags.HasErrors()).To(BeTrue())
+	})
+})
+
+var _ = Describe("File", func() {
+
+	It("rewrites the file in the canonical style", func() {
+		src := lines(`selector "a" {`, `  target = "#a"`, `  by = "query"`, `}`)
+		Expect(string(format.File("a.autog", []byte(src), nil))).To(Equal(
+			lines(`selector "a" {`, `  target = "#a"`, `  by     = "QUERY"`, `}`),
+		))
+	})
+
+	It("applies source formatting to a file with syntax errors", func() {
+		src := lines(`selector "a" {`, `  target = "#a"`, `  by = "query"`, `}`, `automation {`)
+		Expect(string(format.File("a.autog", []byte(src), nil))).To(Equal(
+			lines(`selector "a" {`, `  target = "#a"`, `  by     = "query"`, `}`, `automation {`),
+		))
+	})
+})
+
+var _ = Describe("Diff", func() {
+
+	It("is empty when the sources are equal", func() {
+		Expect(format.Diff("a", "b", []byte("x\n"), []byte("x\n"))).To(BeEmpty())
+	})
+
+	It("writes hunks with context", func() {
+		oldSrc := lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12")
+		newSrc := lines("1", "2", "three", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13")
+		Expect(string(format.Diff("a.autog", "a.autog", []byte(oldSrc), []byte(newSrc)))).To(Equal(lines(
+			"--- a.autog",
+			"+++ a.autog",
+			"@@ -1,6 +1,6 @@",
+			" 1",
+			" 2",
+			"-3",
+			"+three",
+			" 4",
+			" 5",
+			" 6",
+			"@@ -10,3 +10,4 @@",
+			" 10",
+			" 11",
+			" 12",
+			"+13",
+		)))
+	})
+})
@@ -0,0 +1,545 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// commentKey is the property which contains comments in the JSON syntax
+const commentKey = "//"
+
+// selectorRefPattern matches the references to named selectors, which are
+// written as strings in the JSON syntax
+var selectorRefPattern = regexp.MustCompile(`^(selector\.[\w-]+|page\.[\w-]+\.[\w-]+)$`)
+
+// ConvertedName gets the name of the file which results from converting the
+// file to the other syntax
+func ConvertedName(filename string) string {
+	if name, ok := strings.CutSuffix(filename, ".json"); ok {
+		return name
+	}
+	return filename + ".json"
+}
+
+// ToJSON converts a file in the native syntax to the JSON syntax. Expressions
+// which have no equivalent JSON value are written as templates. The comments
+// of each body are kept in its "//" property.
+func ToJSON(filename string, src []byte) ([]byte, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	w := &jsonWriter{src: src}
+	for _, tok := range tokens {
+		if tok.Type == hclsyntax.TokenComment {
+			w.comments = append(w.comments, tok)
+		}
+	}
+
+	w.body(file.Body.(*hclsyntax.Body), 0, len(src))
+	out, err := JSON(w.buf.Bytes())
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to convert file",
+				Detail:   fmt.Sprintf("The file %q could not be converted: %s.", filename, err),
+			},
+		}
+	}
+	return out, nil
+}
+
+type jsonWriter struct {
+	src      []byte
+	buf      bytes.Buffer
+	comments []hclsyntax.Token
+}
+
+// body writes the attributes and blocks of the body in the order of the
+// source. Blocks of the same type are written as separate properties so that
+// their order is kept.
+func (w *jsonWriter) body(body *hclsyntax.Body, start, end int) {
+	type item struct {
+		pos   int
+		attr  *hclsyntax.Attribute
+		block *hclsyntax.Block
+	}
+	var items []item
+	for _, a := range body.Attributes {
+		items = append(items, item{pos: a.SrcRange.Start.Byte, attr: a})
+	}
+	for _, b := range body.Blocks {
+		items = append(items, item{pos: b.TypeRange.Start.Byte, block: b})
+	}
+	slices.SortFunc(items, func(x, y item) int {
+		return x.pos - y.pos
+	})
+
+	w.buf.WriteByte('{')
+	first := true
+	member := func(key string) {
+		if !first {
+			w.buf.WriteByte(',')
+		}
+		first = false
+		w.string(key)
+		w.buf.WriteByte(':')
+	}
+
+	if c := w.bodyComments(body, start, end); c != "" {
+		member(commentKey)
+		w.string(c)
+	}
+	for _, it := range items {
+		if it.attr != nil {
+			member(it.attr.Name)
+			w.expr(it.attr.Name, it.attr.Expr)
+			continue
+		}
+
+		b := it.block
+		member(b.Type)
+		for _, label := range b.Labels {
+			w.buf.WriteByte('{')
+			w.string(label)
+			w.buf.WriteByte(':')
+		}
+		w.body(b.Body, b.OpenBraceRange.End.Byte, b.CloseBraceRange.Start.Byte)
+		for range b.Labels {
+			w.buf.WriteByte('}')
+		}
+	}
+	w.buf.WriteByte('}')
+}
+
+// bodyComments gets the text of the comments which are within the range of
+// the body but not within its blocks
+func (w *jsonWriter) bodyComments(body *hclsyntax.Body, start, end int) string {
+	var lines []string
+	for _, c := range w.comments {
+		pos := c.Range.Start.Byte
+		if pos < start || pos >= end {
+			continue
+		}
+		inBlock := slices.ContainsFunc(body.Blocks, func(b *hclsyntax.Block) bool {
+			return b.OpenBraceRange.Start.Byte <= pos && pos < b.CloseBraceRange.End.Byte
+		})
+		if !inBlock {
+			lines = append(lines, commentText(c.Bytes)...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commentText(comment []byte) []string {
+	text := strings.TrimSpace(string(comment))
+	switch {
+	case strings.HasPrefix(text, "#"):
+		text = strings.TrimPrefix(text, "#")
+	case strings.HasPrefix(text, "//"):
+		text = strings.TrimPrefix(text, "//")
+	default:
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return lines
+}
+
+func (w *jsonWriter) expr(name string, e hclsyntax.Expression) {
+	switch e := e.(type) {
+	case *hclsyntax.TupleConsExpr:
+		w.buf.WriteByte('[')
+		for i, item := range e.Exprs {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.expr("", item)
+		}
+		w.buf.WriteByte(']')
+		return
+
+	case *hclsyntax.ObjectConsExpr:
+		if w.object(e) {
+			return
+		}
+
+	case *hclsyntax.TemplateExpr:
+		w.template(e)
+		return
+
+	case *hclsyntax.TemplateWrapExpr:
+		w.string("${" + w.source(e.Wrapped.Range()) + "}")
+		return
+
+	case *hclsyntax.ScopeTraversalExpr, *hclsyntax.FunctionCallExpr:
+		// These attributes are decoded from the expression itself, which is
+		// parsed from the string in the JSON syntax
+		source := w.source(e.Range())
+		if name == "type" || name == "selector" && selectorRefPattern.MatchString(source) {
+			w.string(source)
+			return
+		}
+	}
+
+	if len(e.Variables()) == 0 {
+		if v, diags := e.Value(nil); !diags.HasErrors() && v.IsWhollyKnown() {
+			w.value(v)
+			return
+		}
+	}
+	w.string("${" + w.source(e.Range()) + "}")
+}
+
+// object writes an object whose keys are all names or literal strings, which
+// returns false for other objects
+func (w *jsonWriter) object(e *hclsyntax.ObjectConsExpr) bool {
+	keys := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		if keys[i] = hcl.ExprAsKeyword(item.KeyExpr); keys[i] != "" {
+			continue
+		}
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || !key.IsKnown() || key.Type() != cty.String {
+			return false
+		}
+		keys[i] = key.AsString()
+	}
+
+	w.buf.WriteByte('{')
+	for i, item := range e.Items {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+		w.string(keys[i])
+		w.buf.WriteByte(':')
+		w.expr("", item.ValueExpr)
+	}
+	w.buf.WriteByte('}')
+	return true
+}
+
+// template writes a template as a string, where its literal parts are
+// escaped and its interpolations are written from their source
+func (w *jsonWriter) template(e *hclsyntax.TemplateExpr) {
+	var sb strings.Builder
+	for _, part := range e.Parts {
+		if lit, ok := part.(*hclsyntax.LiteralValueExpr); ok && lit.Val.Type() == cty.String {
+			sb.WriteString(escapeTemplate(lit.Val.AsString()))
+			continue
+		}
+
+		start := part.Range().Start.Byte
+		if !bytes.HasSuffix(bytes.TrimRight(w.src[:start], " \t~"), []byte("${")) {
+			// Template directives are written from their source
+			r := e.SrcRange
+			w.string(strings.Trim(w.source(r), `"`))
+			return
+		}
+		sb.WriteString("${" + w.source(part.Range()) + "}")
+	}
+	w.string(sb.String())
+}
+
+func (w *jsonWriter) value(v cty.Value) {
+	ty := v.Type()
+	switch {
+	case v.IsNull():
+		w.buf.WriteString("null")
+	case ty == cty.String:
+		w.string(escapeTemplate(v.AsString()))
+	case ty == cty.Number:
+		w.buf.WriteString(v.AsBigFloat().Text('f', -1))
+	case ty == cty.Bool:
+		fmt.Fprint(&w.buf, v.True())
+	case ty.IsObjectType() || ty.IsMapType():
+		m := v.AsValueMap()
+		w.buf.WriteByte('{')
+		for i, k := range slices.Sorted(maps.Keys(m)) {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.string(k)
+			w.buf.WriteByte(':')
+			w.value(m[k])
+		}
+		w.buf.WriteByte('}')
+	default:
+		w.buf.WriteByte('[')
+		for i, item := range v.AsValueSlice() {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.value(item)
+		}
+		w.buf.WriteByte(']')
+	}
+}
+
+func (w *jsonWriter) string(s string) {
+	enc := json.NewEncoder(&w.buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	// Remove the newline written by the encoder
+	w.buf.Truncate(w.buf.Len() - 1)
+}
+
+func (w *jsonWriter) source(r hcl.Range) string {
+	return string(w.src[r.Start.Byte:r.End.Byte])
+}
+
+// escapeTemplate escapes the sequences which would otherwise start an
+// interpolation or directive of a template
+func escapeTemplate(s string) string {
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
+
+// ToHCL converts a file in the JSON syntax to the native syntax. The schema
+// of each block determines whether a property is a block or an attribute.
+// The "//" property of each body is written as comments.
+func ToHCL(src []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+
+	root, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if root.kind != '{' {
+		return nil, fmt.Errorf("the root of the file must be an object")
+	}
+
+	w := &hclWriter{}
+	if err := w.body(root, nil); err != nil {
+		return nil, err
+	}
+	return hclwrite.Format(w.buf.Bytes()), nil
+}
+
+// jsonValue is a value decoded from JSON which keeps the order of the
+// properties of objects, including those with the same name
+type jsonValue struct {
+	kind    byte // '{', '[' or 0 for scalars
+	members []jsonMember
+	items   []*jsonValue
+	scalar  any
+}
+
+type jsonMember struct {
+	key   string
+	value *jsonValue
+}
+
+func decodeJSONValue(dec *json.Decoder) (*jsonValue, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		v := &jsonValue{kind: '{'}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			v.members = append(v.members, jsonMember{key.(string), value})
+		}
+		_, err := dec.Token()
+		return v, err
+
+	case json.Delim('['):
+		v := &jsonValue{kind: '['}
+		for dec.More() {
+			item, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			v.items = append(v.items, item)
+		}
+		_, err := dec.Token()
+		return v, err
+	}
+	return &jsonValue{scalar: tok}, nil
+}
+
+type hclWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *hclWriter) body(obj *jsonValue, path []string) error {
+	schema := config.BlockSchema(path...)
+	for _, m := range obj.members {
+		if m.key == commentKey {
+			if s, ok := m.value.scalar.(string); ok {
+				for _, line := range strings.Split(s, "\n") {
+					fmt.Fprintf(&w.buf, "# %s\n", line)
+				}
+				continue
+			}
+		}
+
+		if header := blockHeader(schema, m.key, m.value); header != nil {
+			err := w.blocks(m.key, len(header.LabelNames), m.value, nil, slices.Concat(path, []string{m.key}))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Fprintf(&w.buf, "%s = ", m.key)
+		w.expr(m.key, m.value)
+		w.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// blockHeader gets the header of the blocks which a property declares. Nil is
+// returned when it is an attribute. A name such as selector can be either, in
+// which case only an object is a block.
+func blockHeader(schema *hcl.BodySchema, blockType string, v *jsonValue) *hcl.BlockHeaderSchema {
+	if schema == nil {
+		return nil
+	}
+	for _, a := range schema.Attributes {
+		if a.Name == blockType && v.kind != '{' && v.kind != '[' {
+			return nil
+		}
+	}
+	for i, b := range schema.Blocks {
+		if b.Type == blockType {
+			return &schema.Blocks[i]
+		}
+	}
+	return nil
+}
+
+// blocks writes the blocks of a property. The labels of the blocks are given
+// by nested objects, and an array contains several blocks.
+func (w *hclWriter) blocks(blockType string, nlabels int, v *jsonValue, labels []string, path []string) error {
+	if v.kind == '[' {
+		for _, item := range v.items {
+			if err := w.blocks(blockType, nlabels, item, labels, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if v.kind != '{' {
+		return fmt.Errorf("the %s block must be an object", blockType)
+	}
+
+	if len(labels) < nlabels {
+		for _, m := range v.members {
+			if m.key == commentKey {
+				continue
+			}
+			if err := w.blocks(blockType, nlabels, m.value, append(labels, m.key), path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w.buf.WriteString(blockType)
+	for _, l := range labels {
+		fmt.Fprintf(&w.buf, " %s", quoteString(l))
+	}
+	w.buf.WriteString(" {\n")
+	if err := w.body(v, path); err != nil {
+		return err
+	}
+	w.buf.WriteString("}\n")
+	return nil
+}
+
+func (w *hclWriter) expr(name string, v *jsonValue) {
+	switch v.kind {
+	case '[':
+		w.buf.WriteByte('[')
+		for i, item := range v.items {
+			if i > 0 {
+				w.buf.WriteString(", ")
+			}
+			w.expr("", item)
+		}
+		w.buf.WriteByte(']')
+		return
+
+	case '{':
+		w.buf.WriteString("{\n")
+		for _, m := range v.members {
+			key := m.key
+			if !hclsyntax.ValidIdentifier(key) {
+				key = quoteString(key)
+			}
+			fmt.Fprintf(&w.buf, "%s = ", key)
+			w.expr("", m.value)
+			w.buf.WriteByte('\n')
+		}
+		w.buf.WriteByte('}')
+		return
+	}
+
+	switch s := v.scalar.(type) {
+	case nil:
+		w.buf.WriteString("null")
+	case string:
+		if name == "type" || name == "selector" && selectorRefPattern.MatchString(s) {
+			w.buf.WriteString(s)
+			return
+		}
+		// A string which contains only an interpolation is written as the
+		// interpolated expression
+		expr, diags := hclsyntax.ParseTemplate([]byte(s), "", hcl.InitialPos)
+		if wrap, ok := expr.(*hclsyntax.TemplateWrapExpr); ok && !diags.HasErrors() {
+			r := wrap.Wrapped.Range()
+			w.buf.WriteString(s[r.Start.Byte:r.End.Byte])
+			return
+		}
+		w.buf.WriteString(quoteString(s))
+	default:
+		fmt.Fprint(&w.buf, s)
+	}
+}
+
+// quoteString quotes a template so that it can be written in the native
+// syntax. Sequences which start interpolations are kept.
+func quoteString(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + r.Replace(s) + `"`
+}
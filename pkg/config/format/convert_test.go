@@ -0,0 +1,134 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/config/format"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// summarize describes the declarations and tasks of a file so that files can
+// be compared regardless of their syntax
+func summarize(f *config.File) []string {
+	var res []string
+	for _, a := range f.Automations {
+		res = append(res, "automation "+a.Name)
+		config.WalkTasks(a.Tasks, func(t config.Task) {
+			res = append(res, fmt.Sprintf("%T", t))
+		})
+		for _, o := range f.Automations {
+			res = append(res, fmt.Sprintf("outputs %d", len(o.Outputs)))
+		}
+	}
+	for _, v := range f.Variables {
+		res = append(res, fmt.Sprintf("variable %s %s", v.Name, v.Type.FriendlyName()))
+	}
+	for _, l := range f.Locals {
+		res = append(res, "local "+l.Name)
+	}
+	for _, s := range f.Selectors {
+		res = append(res, fmt.Sprintf("selector %s %s %s", s.Name, s.By, s.On))
+	}
+	for _, p := range f.Pages {
+		res = append(res, fmt.Sprintf("page %s %d", p.Name, len(p.Selectors)))
+	}
+	for _, b := range f.Browsers {
+		res = append(res, "browser "+b.Name)
+	}
+	for _, d := range f.Devices {
+		res = append(res, fmt.Sprintf("device %s %dx%d", d.Name, d.Width, d.Height))
+	}
+	return res
+}
+
+var _ = Describe("ToJSON", func() {
+
+	examples, _ := filepath.Glob("../testdata/valid-examples/*.autog")
+
+	for _, example := range examples {
+		It("converts "+filepath.Base(example)+" in both directions", func() {
+			src, err := os.ReadFile(example)
+			Expect(err).NotTo(HaveOccurred())
+
+			p := config.NewParser(nil)
+			original, diags := p.ParseSource(example, src)
+			Expect(diags).To(BeEmpty())
+
+			jsonSrc, diags := format.ToJSON(example, src)
+			Expect(diags).To(BeEmpty())
+			converted, diags := p.ParseSource(example+".json", jsonSrc)
+			Expect(diags).To(BeEmpty(), string(jsonSrc))
+			Expect(summarize(converted)).To(Equal(summarize(original)))
+
+			hclSrc, err := format.ToHCL(jsonSrc)
+			Expect(err).NotTo(HaveOccurred())
+			back, diags := p.ParseSource(example+".2.autog", hclSrc)
+			Expect(diags).To(BeEmpty(), string(hclSrc))
+			Expect(summarize(back)).To(Equal(summarize(original)))
+		})
+	}
+
+	It("keeps comments", func() {
+		src := "# Signs in\nautomation \"login\" {\n  // Opens the page\n  navigate {\n    url = \"https://example.com\"\n  }\n}\n"
+		jsonSrc, diags := format.ToJSON("a.autog", []byte(src))
+		Expect(diags).To(BeEmpty())
+		Expect(string(jsonSrc)).To(ContainSubstring(`"//": "Signs in"`))
+		Expect(string(jsonSrc)).To(ContainSubstring(`"//": "Opens the page"`))
+
+		hclSrc, err := format.ToHCL(jsonSrc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(hclSrc)).To(Equal(`# Signs in
+automation "login" {
+  # Opens the page
+  navigate {
+    url = "https://example.com"
+  }
+}
+`))
+	})
+
+	It("writes expressions as templates", func() {
+		src := "automation \"a\" {\n  navigate {\n    url = \"${var.base}/x?n=${length(var.items)}\"\n  }\n  eval \"e\" {\n    script = var.script\n  }\n}\n"
+		jsonSrc, diags := format.ToJSON("a.autog", []byte(src))
+		Expect(diags).To(BeEmpty())
+		Expect(string(jsonSrc)).To(ContainSubstring(`"url": "${var.base}/x?n=${length(var.items)}"`))
+		Expect(string(jsonSrc)).To(ContainSubstring(`"script": "${var.script}"`))
+
+		hclSrc, err := format.ToHCL(jsonSrc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(hclSrc)).To(ContainSubstring(`script = var.script`))
+	})
+})
+
+var _ = Describe("JSON", func() {
+
+	It("formats canonically and keeps the order of properties", func() {
+		out, err := format.JSON([]byte(`{"b":1,  "a": {"x":[1,2]}, "b": 2}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(`{
+  "b": 1,
+  "a": {
+    "x": [
+      1,
+      2
+    ]
+  },
+  "b": 2
+}
+`))
+	})
+
+	It("reports invalid JSON", func() {
+		_, err := format.JSON([]byte(`{"a":`))
+		Expect(err).To(HaveOccurred())
+	})
+})
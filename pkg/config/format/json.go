@@ -0,0 +1,38 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// IsJSON determines whether the file uses the JSON syntax, which is
+// indicated by its extension
+func IsJSON(filename string) bool {
+	return strings.HasSuffix(filename, ".json")
+}
+
+// JSON applies source formatting to a file which uses the JSON syntax. The
+// order of properties is kept, including properties with the same name,
+// which the JSON syntax uses for blocks of the same type.
+func JSON(inSrc []byte) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, inSrc); err != nil {
+		return nil, err
+	}
+	if compact.Len() == 0 {
+		return nil, errors.New("unexpected end of JSON input")
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
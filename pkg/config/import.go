@@ -0,0 +1,55 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Import makes the automations declared in another file or directory
+// available within the package named by its label. Automations within the
+// package are referenced as @NAME/AUTOMATION. The source is a local path
+// relative to the directory of the file which contains the import.
+type Import struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Source    string
+}
+
+var (
+	importBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source", Required: true},
+		},
+	}
+)
+
+func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
+	i := new(Import)
+	res, diags := reduce(
+		i,
+		block,
+		supportsDeclRange(&i.DeclRange),
+		supportsOptionalLabel(&i.Name, &i.NameRange),
+		supportsPartialContentSchema(
+			importBlockSchema,
+			withAttribute("source", &i.Source),
+		),
+	)
+
+	// Package names cannot themselves be package-scoped
+	if err := checkName(i.Name); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid package name %q", i.Name),
+			Detail:   err.Error(),
+			Subject:  &i.NameRange,
+		})
+	}
+	return res, diags
+}
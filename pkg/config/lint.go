@@ -0,0 +1,56 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+)
+
+// Lint configures the rules which are checked by the linter. Rules maps the
+// name of each rule to whether it is enabled. Rules which are not listed keep
+// their default.
+type Lint struct {
+	DeclRange  hcl.Range
+	Rules      map[string]bool
+	RulesRange hcl.Range
+}
+
+var (
+	lintBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "rules"},
+		},
+	}
+)
+
+func decodeLintBlock(block *hcl.Block) (*Lint, hcl.Diagnostics) {
+	l := new(Lint)
+	return reduce(
+		l,
+		block,
+		supportsDeclRange(&l.DeclRange),
+		supportsPartialContentSchema(
+			lintBlockSchema,
+			withAttr("rules", func(attr *hcl.Attribute) hcl.Diagnostics {
+				l.RulesRange = attr.Expr.Range()
+				return gohcl.DecodeExpression(attr.Expr, nil, &l.Rules)
+			}),
+		),
+	)
+}
+
+func checkDuplicateLint(prev, l *Lint) hcl.Diagnostics {
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Duplicate lint block",
+			Detail:   fmt.Sprintf("The lint block was already declared at %s.", prev.DeclRange),
+			Subject:  &l.DeclRange,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Local is a named value computed from an expression, declared within a
+// locals block. Locals can refer to variables and to other locals.
+type Local struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Value     hcl.Expression
+}
+
+func decodeLocalsBlock(block *hcl.Block) ([]*Local, hcl.Diagnostics) {
+	attrs, diags := block.Body.JustAttributes()
+	if len(attrs) == 0 {
+		return nil, diags
+	}
+
+	locals := make([]*Local, 0, len(attrs))
+	for name, attr := range attrs {
+		if err := checkName(name); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid identifier name %q", name),
+				Detail:   err.Error(),
+				Subject:  &attr.NameRange,
+			})
+			continue
+		}
+		locals = append(locals, &Local{
+			DeclRange: attr.Range,
+			NameRange: attr.NameRange,
+			Name:      name,
+			Value:     attr.Expr,
+		})
+	}
+
+	// Attributes are unordered, so restore the order of declaration
+	slices.SortFunc(locals, func(x, y *Local) int {
+		return cmp.Compare(x.DeclRange.Start.Byte, y.DeclRange.Start.Byte)
+	})
+	return locals, diags
+}
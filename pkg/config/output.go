@@ -0,0 +1,79 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Output is a value which is written to the result of running an automation.
+// When an automation declares outputs, only these values appear in the
+// result. The value of a sensitive output is redacted.
+type Output struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Value     hcl.Expression
+	Sensitive bool
+}
+
+var (
+	outputBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "value", Required: true},
+			{Name: "sensitive"},
+		},
+	}
+
+	mappingOutputBlocks = blockMapping[*Output]{
+		"output": decodeOutputBlock,
+	}
+)
+
+func decodeOutputBlock(block *hcl.Block) (*Output, hcl.Diagnostics) {
+	o := new(Output)
+	res, diags := reduce(
+		o,
+		block,
+		supportsDeclRange(&o.DeclRange),
+		supportsOptionalLabel(&o.Name, &o.NameRange),
+		supportsPartialContentSchema(
+			outputBlockSchema,
+			withAttributeExpression("value", &o.Value),
+			withAttribute("sensitive", &o.Sensitive),
+		),
+	)
+
+	// Output names become keys in the result and cannot be package-scoped
+	if err := checkName(o.Name); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid output name %q", o.Name),
+			Detail:   err.Error(),
+			Subject:  &o.NameRange,
+		})
+	}
+	return res, diags
+}
+
+func checkDuplicateOutputs(outputs []*Output) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	seen := map[string]*Output{}
+	for _, o := range outputs {
+		if prev, ok := seen[o.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate output",
+				Detail:   fmt.Sprintf("The output %q was already declared at %s.", o.Name, prev.DeclRange),
+				Subject:  &o.DeclRange,
+			})
+			continue
+		}
+		seen[o.Name] = o
+	}
+	return diags
+}
@@ -14,6 +14,8 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	"github.com/onsi/gomega/types"
 	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 var _ = Describe("LoadFile", func() {
@@ -45,7 +47,7 @@ var _ = Describe("LoadFile", func() {
 					BeAssignableToTypeOf(&config.Eval{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
 						"Name":   Equal("output"),
-						"Script": Equal("1"),
+						"Script": WithTransform(toString, Equal("1")),
 					}))),
 			})),
 
@@ -54,10 +56,50 @@ var _ = Describe("LoadFile", func() {
 					BeAssignableToTypeOf(&config.InnerHTML{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
 						"Name":     Equal("content"),
-						"Selector": Equal("#aubergine"),
+						"Selector": WithTransform(toString, Equal("#aubergine")),
 						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
-							"AtLeast":       PointTo(Equal(1)),
-							"RetryInterval": PointTo(Equal(5 * time.Second)),
+							"AtLeast":       WithTransform(toInt, Equal(1)),
+							"RetryInterval": WithTransform(toDuration, Equal(5*time.Second)),
+						})),
+					}))),
+			})),
+
+			Entry("capture", "capture.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"1": And(
+					BeAssignableToTypeOf(&config.Text{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":     Equal("heading"),
+						"Selector": WithTransform(toString, Equal("h1")),
+					}))),
+				"2": And(
+					BeAssignableToTypeOf(&config.Attribute{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":      Equal("link"),
+						"Attribute": Equal("href"),
+						"Selector":  WithTransform(toString, Equal("a.more")),
+					}))),
+				"3": And(
+					BeAssignableToTypeOf(&config.Value{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name": Equal("email"),
+						"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+							"Target": WithTransform(toString, Equal("#email")),
+							"By":     Equal(config.ByID),
+						}))),
+					}))),
+				"4": And(
+					BeAssignableToTypeOf(&config.OuterHTML{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":     Equal("footer"),
+						"Selector": WithTransform(toString, Equal("footer")),
+					}))),
+				"5": And(
+					BeAssignableToTypeOf(&config.Count{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":     Equal("rows"),
+						"Selector": WithTransform(toString, Equal("table tr")),
+						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
+							"AtLeast": WithTransform(toInt, Equal(1)),
 						})),
 					}))),
 			})),
@@ -66,10 +108,10 @@ var _ = Describe("LoadFile", func() {
 				"1": And(
 					BeAssignableToTypeOf(&config.Blur{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Selector": Equal("#grape"),
+						"Selector": WithTransform(toString, Equal("#grape")),
 						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
-							"AtLeast":       PointTo(Equal(2)),
-							"RetryInterval": PointTo(Equal(5 * time.Minute)),
+							"AtLeast":       WithTransform(toInt, Equal(2)),
+							"RetryInterval": WithTransform(toDuration, Equal(5*time.Minute)),
 						})),
 					}))),
 			})),
@@ -78,7 +120,7 @@ var _ = Describe("LoadFile", func() {
 				"1": And(
 					BeAssignableToTypeOf(&config.Clear{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Selector": Equal("#ivy"),
+						"Selector": WithTransform(toString, Equal("#ivy")),
 					}))),
 			})),
 
@@ -86,7 +128,7 @@ var _ = Describe("LoadFile", func() {
 				"1": And(
 					BeAssignableToTypeOf(&config.Click{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Selector": Equal("#olive"),
+						"Selector": WithTransform(toString, Equal("#olive")),
 					}))),
 				"2": And(
 					BeAssignableToTypeOf(&config.Click{}),
@@ -95,7 +137,7 @@ var _ = Describe("LoadFile", func() {
 							"0": And(
 								BeAssignableToTypeOf(&config.Selector{}),
 								PointTo(MatchFields(IgnoreExtras, Fields{
-									"Target": Equal("#raspberry"),
+									"Target": WithTransform(toString, Equal("#raspberry")),
 								}))),
 						}),
 					}))),
@@ -121,7 +163,7 @@ var _ = Describe("LoadFile", func() {
 							"0": And(
 								BeAssignableToTypeOf(&config.Selector{}),
 								PointTo(MatchFields(IgnoreExtras, Fields{
-									"Target": Equal("#yellow"),
+									"Target": WithTransform(toString, Equal("#yellow")),
 								}))),
 						}),
 					}))),
@@ -131,11 +173,11 @@ var _ = Describe("LoadFile", func() {
 				"1": And(
 					BeAssignableToTypeOf(&config.SendKeys{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Selector": Equal("#grape"),
+						"Selector": WithTransform(toString, Equal("#grape")),
 						"Keys":     WithTransform(toString, Equal("hello world")),
 						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
-							"AtLeast":       PointTo(Equal(2)),
-							"RetryInterval": PointTo(Equal(5 * time.Minute)),
+							"AtLeast":       WithTransform(toInt, Equal(2)),
+							"RetryInterval": WithTransform(toDuration, Equal(5*time.Minute)),
 						})),
 					}))),
 			})),
@@ -144,10 +186,10 @@ var _ = Describe("LoadFile", func() {
 				"1": And(
 					BeAssignableToTypeOf(&config.WaitVisible{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Selector": Equal("#aubergine"),
+						"Selector": WithTransform(toString, Equal("#aubergine")),
 						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
-							"AtLeast":       PointTo(Equal(1)),
-							"RetryInterval": PointTo(Equal(5 * time.Second)),
+							"AtLeast":       WithTransform(toInt, Equal(1)),
+							"RetryInterval": WithTransform(toDuration, Equal(5*time.Second)),
 						})),
 					}))),
 			})),
@@ -157,7 +199,7 @@ var _ = Describe("LoadFile", func() {
 					BeAssignableToTypeOf(&config.Screenshot{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
 						"Name":     Equal("label_png"),
-						"Selector": Equal("#aubergine"),
+						"Selector": WithTransform(toString, Equal("#aubergine")),
 					}))),
 				"2": And(
 					BeAssignableToTypeOf(&config.Screenshot{}),
@@ -166,16 +208,221 @@ var _ = Describe("LoadFile", func() {
 					}))),
 			})),
 
+			Entry("expressions", "expressions.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selector": WithTransform(variables, ConsistOf("var")),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Script": WithTransform(variables, ConsistOf("var")),
+				})),
+				"2": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Duration": WithTransform(variables, ConsistOf("var")),
+				})),
+				"3": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Target": WithTransform(variables, ConsistOf("var")),
+					}))),
+					"Options": PointTo(MatchFields(IgnoreExtras, Fields{
+						"AtLeast": WithTransform(variables, ConsistOf("var")),
+					})),
+				})),
+			})),
+
 			Entry("sleep", "sleep.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
 				"0": And(
 					BeAssignableToTypeOf(&config.Sleep{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Duration": Equal(5 * time.Second),
+						"Duration": WithTransform(toDuration, Equal(5*time.Second)),
 					}))),
 				"1": And(
 					BeAssignableToTypeOf(&config.Sleep{}),
 					PointTo(MatchFields(IgnoreExtras, Fields{
-						"Duration": Equal(0 * time.Second),
+						"Duration": BeNil(),
+					}))),
+			})),
+
+			Entry("import", "import.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": And(
+					BeAssignableToTypeOf(&config.Flow{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name": Equal("@shared/login"),
+					}))),
+				"1": And(
+					BeAssignableToTypeOf(&config.Flow{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name": Equal("checkout"),
+					}))),
+			})),
+
+			Entry("if", "if.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"2": And(
+					BeAssignableToTypeOf(&config.If{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Condition": Not(BeNil()),
+						"Tasks": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Click{}),
+						}),
+						"Else": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Title{}),
+						}),
+					}))),
+				"3": And(
+					BeAssignableToTypeOf(&config.If{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Tasks": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Selector": WithTransform(toString, Equal("#accept")),
+							})),
+						}),
+						"Else": BeEmpty(),
+					}))),
+			})),
+
+			Entry("assert", "assert.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"1": And(
+					BeAssignableToTypeOf(&config.Assert{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Title":   WithTransform(toString, Equal("Example Domain")),
+						"Message": Not(BeNil()),
+					}))),
+				"2": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selector":     WithTransform(toString, Equal("h1")),
+					"TextContains": Not(BeNil()),
+				})),
+				"3": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selectors": HaveLen(1),
+					"Count":     Not(BeNil()),
+				})),
+				"4": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Attribute": Equal("href"),
+					"Value":     Not(BeNil()),
+				})),
+				"6": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Condition": Not(BeNil()),
+				})),
+			})),
+
+			Entry("extract", "extract.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"1": And(
+					BeAssignableToTypeOf(&config.Extract{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name": Equal("products"),
+						"Root": BeNil(),
+						"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+							"Ref": Equal("selector.product"),
+						}))),
+						"Next":     WithTransform(toString, Equal("a.next")),
+						"MaxPages": Equal(5),
+						"Fields": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Name":     Equal("name"),
+								"Selector": WithTransform(toString, Equal("h2")),
+								"Type":     Equal(cty.String),
+							})),
+							"1": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Name": Equal("price"),
+								"Type": Equal(cty.Number),
+							})),
+							"2": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Name":      Equal("link"),
+								"Attribute": Equal("href"),
+							})),
+							"3": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Name":      Equal("sku"),
+								"Attribute": Equal("data-sku"),
+							})),
+						}),
+						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
+							"AtLeast": WithTransform(toInt, Equal(1)),
+						})),
+					}))),
+			})),
+
+			Entry("flow", "flow.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": And(
+					BeAssignableToTypeOf(&config.Flow{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name": Equal("login"),
+						"Args": Not(BeNil()),
+					}))),
+			})),
+
+			Entry("for_each", "for_each.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": And(
+					BeAssignableToTypeOf(&config.ForEach{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":      Equal("pages"),
+						"Items":     Not(BeNil()),
+						"Selectors": BeEmpty(),
+						"Tasks": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Navigate{}),
+							"1": BeAssignableToTypeOf(&config.Title{}),
+						}),
+					}))),
+				"1": And(
+					BeAssignableToTypeOf(&config.ForEach{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Name":  Equal("results"),
+						"Items": BeNil(),
+						"Selectors": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": PointTo(MatchFields(IgnoreExtras, Fields{
+								"Target": WithTransform(toString, Equal(".result")),
+								"By":     Equal(config.ByQueryAll),
+							})),
+						}),
+						"Tasks": HaveLen(1),
+					}))),
+			})),
+
+			Entry("retry", "retry.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": And(
+					BeAssignableToTypeOf(&config.Retry{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Attempts":   Equal(5),
+						"Backoff":    Equal(2 * time.Second),
+						"Multiplier": Equal(float64(2)),
+						"Tasks":      HaveLen(2),
+					}))),
+				"1": And(
+					BeAssignableToTypeOf(&config.Retry{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Attempts":   Equal(3),
+						"Backoff":    Equal(time.Duration(0)),
+						"Multiplier": Equal(float64(1)),
+					}))),
+			})),
+
+			Entry("timeout", "timeout.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": And(
+					BeAssignableToTypeOf(&config.Timeout{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"BlockType": Equal("navigate"),
+						"Duration":  Equal(10 * time.Second),
+						"Task":      BeAssignableToTypeOf(&config.Navigate{}),
+					}))),
+				"1": And(
+					BeAssignableToTypeOf(&config.Click{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Options": PointTo(MatchFields(IgnoreExtras, Fields{
+							"Timeout": WithTransform(toDuration, Equal(5*time.Second)),
+						})),
+					}))),
+			})),
+
+			Entry("try", "try.autog", MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"1": And(
+					BeAssignableToTypeOf(&config.Try{}),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Tasks": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Click{}),
+						}),
+						"Catch": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Screenshot{}),
+							"1": BeAssignableToTypeOf(&config.NavigateBack{}),
+						}),
+						"Finally": MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+							"0": BeAssignableToTypeOf(&config.Title{}),
+						}),
 					}))),
 			})),
 
@@ -183,6 +430,241 @@ var _ = Describe("LoadFile", func() {
 				"0": BeAssignableToTypeOf(&config.Version{}),
 			})),
 		)
+
+		It("decodes expressions in the JSON syntax", func() {
+			src := `{
+  "automation": {
+    "json": {
+      "sleep": { "duration": "${var.pause}" },
+      "click": {
+        "selector": "#ok",
+        "options": { "retry_interval": "${var.interval}", "at_least": "${var.rows}" }
+      }
+    }
+  }
+}`
+			res, diags := config.NewParser(nil).ParseSource("site.autog.json", []byte(src))
+			Expect(diags).To(BeEmpty())
+			Expect(res.Automations[0].Tasks).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Duration": WithTransform(variables, ConsistOf("var")),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Options": PointTo(MatchFields(IgnoreExtras, Fields{
+						"RetryInterval": WithTransform(variables, ConsistOf("var")),
+						"AtLeast":       WithTransform(variables, ConsistOf("var")),
+					})),
+				})),
+			}))
+		})
+	})
+
+	Describe("parse Variable", func() {
+
+		It("decodes variable blocks", func() {
+			res, err := validExample("variable.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Variables).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":        Equal("base_url"),
+					"Description": Equal("The site under test"),
+					"Type":        Equal(cty.String),
+					"Default":     WithTransform(toString, Equal("https://example.com")),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("retries"),
+					"Type":    Equal(cty.Number),
+					"Default": BeNil(),
+				})),
+			}))
+		})
+	})
+
+	Describe("parse Local", func() {
+
+		It("decodes locals blocks in order of declaration", func() {
+			res, err := validExample("locals.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Locals).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name": Equal("login_url"),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":  Equal("username"),
+					"Value": WithTransform(toString, Equal("admin")),
+				})),
+			}))
+		})
+	})
+
+	Describe("parse Import", func() {
+
+		It("decodes import blocks", func() {
+			res, err := validExample("import.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Imports).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":   Equal("shared"),
+					"Source": Equal("../shared"),
+				})),
+			))
+		})
+	})
+
+	Describe("parse Browser", func() {
+
+		It("decodes browser blocks", func() {
+			res, err := validExample("browser.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Browsers).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("default"),
+					"ExecPath":   PointTo(Equal("/usr/bin/chromium")),
+					"Headless":   PointTo(BeTrue()),
+					"NoSandbox":  PointTo(BeTrue()),
+					"WindowSize": Equal([]int{1280, 720}),
+					"Env":        Equal(map[string]string{"TZ": "UTC"}),
+					"Flags":      Equal(map[string]any{"disable-extensions": true, "lang": "en-US"}),
+					"RemoteURL":  BeNil(),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":             Equal("remote"),
+					"RemoteURL":        PointTo(Equal("ws://127.0.0.1:9222")),
+					"WSURLReadTimeout": PointTo(Equal(30 * time.Second)),
+					"NoModifyURL":      PointTo(BeTrue()),
+					"Headless":         BeNil(),
+				})),
+			}))
+		})
+	})
+
+	Describe("parse Device", func() {
+
+		It("decodes device blocks", func() {
+			res, err := validExample("device.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Devices).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":        Equal("pixel9"),
+					"Description": Equal("Pixel 9"),
+					"Width":       Equal(int64(412)),
+					"Height":      Equal(int64(915)),
+					"Scale":       Equal(2.625),
+					"Mobile":      BeTrue(),
+					"Touch":       BeTrue(),
+					"Landscape":   BeFalse(),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("kiosk"),
+					"Scale":     Equal(1.0),
+					"Landscape": BeTrue(),
+				})),
+			}))
+		})
+
+		It("decodes the lint block", func() {
+			res, err := validExample("lint.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Lint).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Rules": Equal(map[string]bool{"fixed-sleep": false, "prefer-query": true}),
+			})))
+		})
+
+		It("decodes the format block", func() {
+			res, err := validExample("format.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Format).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"EnumCase":      Equal(config.EnumCaseLower),
+				"SelectorStyle": Equal(config.SelectorStylePreserve),
+			})))
+		})
+	})
+
+	Describe("ResolveSelectors", func() {
+
+		It("copies named selectors into references", func() {
+			res, err := validExample("selector.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ResolveSelectors(res)).To(BeEmpty())
+
+			Expect(res.Automations[0].Tasks).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Ref":    Equal("selector.login_button"),
+						"Target": WithTransform(toString, Equal("#login button")),
+						"By":     Equal(config.ByQuery),
+						"On":     Equal(config.OnVisible),
+					}))),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Ref":    Equal("page.checkout.submit"),
+						"Target": WithTransform(toString, Equal("#checkout [type=submit]")),
+					}))),
+				})),
+				"2": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Selector":  WithTransform(toString, Equal("#literal")),
+					"Selectors": BeEmpty(),
+				})),
+			}))
+		})
+
+		It("resolves the references of extract blocks", func() {
+			res, err := validExample("extract.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ResolveSelectors(res)).To(BeEmpty())
+
+			Expect(res.Automations[0].Tasks[1]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Selectors": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Target": WithTransform(toString, Equal(".product")),
+					"By":     Equal(config.ByQueryAll),
+				}))),
+			})))
+		})
+
+		It("reports references to undeclared selectors", func() {
+			res, err := errExample("undeclared-selector.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ResolveSelectors(res)).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Summary": Equal("Reference to undeclared selector"),
+				"Detail":  ContainSubstring("selector.logout_button"),
+			}))))
+		})
+	})
+
+	Describe("parse Automation", func() {
+
+		It("decodes the params and returns attributes", func() {
+			res, err := validExample("flow.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Automations[1]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Params":  Equal([]string{"username"}),
+				"Returns": Not(BeNil()),
+			})))
+		})
+
+		It("decodes the output blocks", func() {
+			res, err := validExample("output.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Automations[0].Tasks).To(HaveLen(3))
+			Expect(res.Automations[0].Outputs).To(MatchElementsWithIndex(IndexIdentity, IgnoreExtras, Elements{
+				"0": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("page"),
+					"Value":     Not(BeNil()),
+					"Sensitive": BeFalse(),
+				})),
+				"1": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("token"),
+					"Sensitive": BeTrue(),
+				})),
+			}))
+		})
+
+		It("decodes the timeout attribute", func() {
+			res, err := validExample("timeout.autog")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Automations[0].Timeout).To(Equal(2 * time.Minute))
+		})
 	})
 
 	DescribeTable("error examples",
@@ -196,6 +678,86 @@ var _ = Describe("LoadFile", func() {
 				"Summary": ContainSubstring(`Invalid identifier name "blur!invalid"`),
 			})),
 		})),
+
+		Entry("assert-without-selector", "assert-without-selector.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid assert block"),
+			"Detail":  ContainSubstring("A selector must be specified"),
+		})))),
+
+		Entry("duplicate-output", "duplicate-output.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate output"),
+			"Detail":  ContainSubstring(`The output "title" was already declared`),
+		})))),
+
+		Entry("duplicate-variable", "duplicate-variable.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate variable"),
+			"Detail":  ContainSubstring(`The variable "base_url" was already declared`),
+		})))),
+
+		Entry("duplicate-browser", "duplicate-browser.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate browser profile"),
+			"Detail":  ContainSubstring(`The browser profile "default" was already declared`),
+		})))),
+
+		Entry("browser-window-size", "browser-window-size.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid window size"),
+		})))),
+
+		Entry("device-dimensions", "device-dimensions.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid device dimensions"),
+		})))),
+
+		Entry("unsupported-block", "unsupported-block.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Unsupported block type"),
+			"Detail":  Equal(`Blocks of type "clik" are not expected here.`),
+		})))),
+
+		Entry("duplicate-lint", "duplicate-lint.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate lint block"),
+		})))),
+
+		Entry("duplicate-format", "duplicate-format.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate format block"),
+		})))),
+
+		Entry("format-enum-case", "format-enum-case.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Cannot convert config.EnumCase"),
+		})))),
+
+		Entry("attribute-without-name", "attribute-without-name.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Missing required argument"),
+		})))),
+
+		Entry("sleep-duration", "sleep-duration.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Cannot convert time.Duration"),
+		})))),
+
+		Entry("retry-attempts", "retry-attempts.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid retry block"),
+		})))),
+
+		Entry("extract-max-pages", "extract-max-pages.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid extract block"),
+		})))),
+
+		Entry("extract-field-type", "extract-field-type.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid field type"),
+		})))),
+
+		Entry("extract-field-without-selector", "extract-field-without-selector.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid field block"),
+			"Detail":  ContainSubstring("A selector must be specified"),
+		})))),
+
+		Entry("extract-duplicate-field", "extract-duplicate-field.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Duplicate field"),
+			"Detail":  ContainSubstring(`"name"`),
+		})))),
+
+		Entry("for-each-items-and-selector", "for-each-items-and-selector.autog", ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Invalid for_each block"),
+			"Detail":  ContainSubstring("Exactly one of items or selector"),
+		})))),
 	)
 })
 
@@ -227,3 +789,23 @@ func toString(v any) any {
 	d, _ := v.(hcl.Expression).Value(nil)
 	return d.AsString()
 }
+
+func variables(v any) any {
+	var names []string
+	for _, t := range v.(hcl.Expression).Variables() {
+		names = append(names, t.RootName())
+	}
+	return names
+}
+
+func toInt(v any) any {
+	var i int
+	d, _ := v.(hcl.Expression).Value(nil)
+	_ = gocty.FromCtyValue(d, &i)
+	return i
+}
+
+func toDuration(v any) any {
+	d, _ := time.ParseDuration(toString(v).(string))
+	return d
+}
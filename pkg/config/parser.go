@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type Parser struct {
@@ -40,6 +41,11 @@ func NewParser(fs fs.FS) *Parser {
 	}
 }
 
+// Files gets the source files which have been parsed, keyed by filename
+func (p *Parser) Files() map[string]*hcl.File {
+	return p.p.Files()
+}
+
 func (p *Parser) loadHCLFile(path string) (hcl.Body, hcl.Diagnostics) {
 	src, err := fs.ReadFile(p.fs, path)
 
@@ -53,6 +59,10 @@ func (p *Parser) loadHCLFile(path string) (hcl.Body, hcl.Diagnostics) {
 		}
 	}
 
+	return p.parseHCL(src, path)
+}
+
+func (p *Parser) parseHCL(src []byte, path string) (hcl.Body, hcl.Diagnostics) {
 	var (
 		file  *hcl.File
 		diags hcl.Diagnostics
@@ -81,6 +91,35 @@ func (p *Parser) LoadFile(path string) (*File, hcl.Diagnostics) {
 	return decodeFile(path, body)
 }
 
+// ParseSource decodes a file from its source, where the path determines
+// whether the source is parsed as JSON
+func (p *Parser) ParseSource(path string, src []byte) (*File, hcl.Diagnostics) {
+	body, diags := p.parseHCL(src, path)
+	file, fileDiags := decodeFile(path, body)
+	return file, append(diags, fileDiags...)
+}
+
+// LoadValuesFile loads a file of variable values, which contains only
+// top-level attributes in the manner of a .tfvars file. The values are
+// evaluated without any variables or functions in scope.
+func (p *Parser) LoadValuesFile(path string) (map[string]cty.Value, hcl.Diagnostics) {
+	body, diags := p.loadHCLFile(path)
+	if body == nil {
+		return nil, diags
+	}
+
+	attrs, attrDiags := body.JustAttributes()
+	diags = append(diags, attrDiags...)
+
+	values := make(map[string]cty.Value, len(attrs))
+	for name, attr := range attrs {
+		v, valueDiags := attr.Expr.Value(nil)
+		diags = append(diags, valueDiags...)
+		values[name] = v
+	}
+	return values, diags
+}
+
 func tryLabel(b *hcl.Block, n int) string {
 	if n < len(b.Labels) {
 		return b.Labels[n]
@@ -5,9 +5,13 @@ package config
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type mapper func(*hcl.Block) hcl.Diagnostics
@@ -57,7 +61,11 @@ func appendsTo[T any, Slice ~[]T](target *Slice, m blockMapping[T]) partialConte
 		var diags hcl.Diagnostics
 		var results []T
 		for _, block := range content.Blocks {
-			cfg, cfgDiags := m[block.Type](block)
+			fn, ok := m[block.Type]
+			if !ok {
+				continue
+			}
+			cfg, cfgDiags := fn(block)
 			diags = append(diags, cfgDiags...)
 			if any(cfg) != nil {
 				results = append(results, cfg)
@@ -71,13 +79,16 @@ func appendsTo[T any, Slice ~[]T](target *Slice, m blockMapping[T]) partialConte
 // contravariant conversion of return type
 func taskMapping[T Task](fn func(*hcl.Block) (T, hcl.Diagnostics)) func(*hcl.Block) (Task, hcl.Diagnostics) {
 	return func(b *hcl.Block) (Task, hcl.Diagnostics) {
-		return fn(b)
+		task, diags := fn(b)
+		res, attrDiags := decodeTaskAttributes(b, task)
+		return res, append(diags, attrDiags...)
 	}
 }
 
 func supportsPartialContentSchema(schema *hcl.BodySchema, att ...partialContentMapper) mapper {
 	return func(block *hcl.Block) hcl.Diagnostics {
 		content, _, diags := block.Body.PartialContent(schema)
+		diags = append(diags, checkUnsupportedBlocks(block.Body, schema)...)
 		for _, a := range att {
 			diags = append(diags, a(content)...)
 		}
@@ -85,6 +96,33 @@ func supportsPartialContentSchema(schema *hcl.BodySchema, att ...partialContentM
 	}
 }
 
+// checkUnsupportedBlocks reports the blocks of the body which are not in the
+// schema. Partial content is used to decode bodies because the attributes of
+// a task block are decoded using more than one schema, but the blocks of the
+// body are always given by a single schema.
+func checkUnsupportedBlocks(body hcl.Body, schema *hcl.BodySchema) hcl.Diagnostics {
+	syntax, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var diags hcl.Diagnostics
+	for _, b := range syntax.Blocks {
+		if slices.ContainsFunc(schema.Blocks, func(s hcl.BlockHeaderSchema) bool {
+			return s.Type == b.Type
+		}) {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported block type",
+			Detail:   fmt.Sprintf("Blocks of type %q are not expected here.", b.Type),
+			Subject:  &b.TypeRange,
+		})
+	}
+	return diags
+}
+
 func withAttr(name string, fn func(*hcl.Attribute) hcl.Diagnostics) partialContentMapper {
 	return func(content *hcl.BodyContent) hcl.Diagnostics {
 		if attr, ok := content.Attributes[name]; ok {
@@ -94,6 +132,18 @@ func withAttr(name string, fn func(*hcl.Attribute) hcl.Diagnostics) partialConte
 	}
 }
 
+func withBlock(blockType string, fn func(*hcl.Block) hcl.Diagnostics) partialContentMapper {
+	return func(content *hcl.BodyContent) hcl.Diagnostics {
+		var diags hcl.Diagnostics
+		for _, block := range content.Blocks {
+			if block.Type == blockType {
+				diags = append(diags, fn(block)...)
+			}
+		}
+		return diags
+	}
+}
+
 func withAttribute(name string, value any) partialContentMapper {
 	return withAttr(name, func(attr *hcl.Attribute) hcl.Diagnostics {
 		return gohcl.DecodeExpression(attr.Expr, nil, value)
@@ -107,28 +157,56 @@ func withAttributeExpression(name string, value *hcl.Expression) partialContentM
 	})
 }
 
+func withAttributeType(name string, value *cty.Type) partialContentMapper {
+	return withAttr(name, func(attr *hcl.Attribute) hcl.Diagnostics {
+		ty, diags := typeexpr.TypeConstraint(attr.Expr)
+		if !diags.HasErrors() {
+			*value = ty
+		}
+		return diags
+	})
+}
+
 func withAttributeParser[T any](name string, valueThunk func(T), parser func(string) (T, error)) partialContentMapper {
 	// A valueThunk is used instead of setting the variable directly because
 	// this function can _also_ be used for attributes that are optional
 	// and represented in their models using pointers (instead of values directly).
-	// An example of this is Options.RetryInterval which is *time.Duration
 	return withAttr(name, func(attr *hcl.Attribute) hcl.Diagnostics {
-		var text string
-
-		diags := gohcl.DecodeExpression(attr.Expr, nil, &text)
-		dur, err := parser(text)
-		if err == nil {
-			valueThunk(dur)
-		} else {
-			var it T
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  fmt.Sprintf("Cannot convert %T", it),
-				Detail:   fmt.Sprintf("Cannot convert %T: %s", it, err.Error()),
-				Subject:  attr.Expr.StartRange().Ptr(),
-				Context:  attr.Expr.Range().Ptr(),
-			})
+		return parseAttribute(attr, valueThunk, parser)
+	})
+}
+
+// withAttributeExpressionParser stores the expression of the attribute so that
+// it can be evaluated when the task runs. When the expression is constant, the
+// parser checks its value so that errors are reported during decoding.
+// An empty context is used because the JSON syntax treats templates as
+// literal strings when there is no context.
+func withAttributeExpressionParser[T any](name string, value *hcl.Expression, parser func(string) (T, error)) partialContentMapper {
+	return withAttr(name, func(attr *hcl.Attribute) hcl.Diagnostics {
+		*value = attr.Expr
+		if _, diags := attr.Expr.Value(&hcl.EvalContext{}); diags.HasErrors() {
+			return nil
 		}
-		return diags
+		return parseAttribute(attr, func(T) {}, parser)
 	})
 }
+
+func parseAttribute[T any](attr *hcl.Attribute, valueThunk func(T), parser func(string) (T, error)) hcl.Diagnostics {
+	var text string
+
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &text)
+	dur, err := parser(text)
+	if err == nil {
+		valueThunk(dur)
+	} else {
+		var it T
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Cannot convert %T", it),
+			Detail:   fmt.Sprintf("Cannot convert %T: %s", it, err.Error()),
+			Subject:  attr.Expr.StartRange().Ptr(),
+			Context:  attr.Expr.Range().Ptr(),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// blockSchemas contains the schema of the body of each type of block. Blocks
+// of the same type share a schema wherever they appear.
+var blockSchemas = map[string]*hcl.BodySchema{
+	"automation": automationBodySchema,
+	"variable":   variableBlockSchema,
+	"locals":     emptyBlockSchema,
+	"import":     importBlockSchema,
+	"selector":   selectorBlockSchema,
+	"page":       pageBlockSchema,
+	"browser":    browserBlockSchema,
+	"device":     deviceBlockSchema,
+	"lint":       lintBlockSchema,
+	"format":     formatBlockSchema,
+	"output":     outputBlockSchema,
+	"options":    optionsBlockSchema,
+	"field":      fieldBlockSchema,
+	"else":       taskBlocksSchema,
+	"catch":      taskBlocksSchema,
+	"finally":    taskBlocksSchema,
+
+	"assert":           taskSchema(assertBlockSchema),
+	"attribute":        taskSchema(attributeBlockSchema),
+	"blur":             taskSchema(blurBlockSchema),
+	"clear":            taskSchema(clearBlockSchema),
+	"click":            taskSchema(clickBlockSchema),
+	"count":            taskSchema(countBlockSchema),
+	"double_click":     taskSchema(doubleClickBlockSchema),
+	"eval":             taskSchema(evalBlockSchema),
+	"extract":          taskSchema(extractBlockSchema),
+	"flow":             taskSchema(flowBlockSchema),
+	"inner_html":       taskSchema(innerHTMLBlockSchema),
+	"navigate":         taskSchema(navigateBlockSchema),
+	"navigate_back":    taskSchema(navigateBackBlockSchema),
+	"navigate_forward": taskSchema(navigateForwardBlockSchema),
+	"outer_html":       taskSchema(outerHTMLBlockSchema),
+	"reload":           taskSchema(reloadBlockSchema),
+	"screenshot":       taskSchema(screenshotBlockSchema),
+	"send_keys":        taskSchema(sendKeysBlockSchema),
+	"sleep":            taskSchema(sleepBlockSchema),
+	"stop":             taskSchema(stopBlockSchema),
+	"text":             taskSchema(textBlockSchema),
+	"title":            taskSchema(titleBlockSchema),
+	"value":            taskSchema(valueBlockSchema),
+	"version":          taskSchema(versionBlockSchema),
+	"wait_visible":     taskSchema(waitVisibleBlockSchema),
+	"if":               taskSchema(ifBlockSchema),
+	"for_each":         taskSchema(forEachBlockSchema),
+	"try":              taskSchema(tryBlockSchema),
+	"retry":            taskSchema(retryBlockSchema),
+}
+
+// taskSchema adds the attributes which are allowed on any task block
+func taskSchema(schema *hcl.BodySchema) *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: slices.Concat(schema.Attributes, taskAttributesSchema.Attributes),
+		Blocks:     schema.Blocks,
+	}
+}
+
+// BlockSchema gets the schema of the body of a block, which is identified by
+// the types of the blocks that contain it starting from the top level of a
+// file. The schema of the file is returned when no types are given. Nil is
+// returned when a block of the type is not allowed where it appears.
+func BlockSchema(path ...string) *hcl.BodySchema {
+	schema := fileSchema
+	for _, blockType := range path {
+		allowed := slices.ContainsFunc(schema.Blocks, func(b hcl.BlockHeaderSchema) bool {
+			return b.Type == blockType
+		})
+		if !allowed {
+			return nil
+		}
+		schema = blockSchemas[blockType]
+	}
+	return schema
+}
@@ -6,15 +6,31 @@ package config
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 )
 
 type Selector struct {
 	DeclRange hcl.Range
-	Target    string
+	NameRange hcl.Range
+	Name      string
+	Target    hcl.Expression
 	By        SelectorBy
 	On        SelectorOn
+
+	// Ref identifies the named selector that this selector refers to, in the
+	// form selector.NAME or page.PAGE.NAME. The target and other attributes
+	// are copied from the named selector by [ResolveSelectors].
+	Ref string
+}
+
+// Page groups named selectors, which are referenced as page.PAGE.NAME
+type Page struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selectors []*Selector
 }
 
 type SelectorBy string
@@ -37,6 +53,14 @@ const (
 	OnNotPresent SelectorOn = "NOT_PRESENT"
 )
 
+// SelectorByValues contains the values which are allowed for the by
+// attribute of a selector
+var SelectorByValues = []SelectorBy{BySearch, ByJSPath, ByID, ByQuery, ByQueryAll}
+
+// SelectorOnValues contains the values which are allowed for the on
+// attribute of a selector
+var SelectorOnValues = []SelectorOn{OnReady, OnVisible, OnNotVisible, OnEnabled, OnSelected, OnNotPresent}
+
 var (
 	selectorBlockSchema = &hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
@@ -46,6 +70,15 @@ var (
 		},
 		Blocks: []hcl.BlockHeaderSchema{},
 	}
+
+	pageBlockSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       "selector",
+				LabelNames: []string{"name"},
+			},
+		},
+	}
 )
 
 func (s *Selector) setOn(n SelectorOn) {
@@ -62,15 +95,139 @@ func decodeSelectorBlock(block *hcl.Block) (*Selector, hcl.Diagnostics) {
 		f,
 		block,
 		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
 		supportsPartialContentSchema(
 			selectorBlockSchema,
-			withAttribute("target", &f.Target),
+			withAttributeExpression("target", &f.Target),
 			withAttributeParser("by", f.setBy, parseSelectorBy),
 			withAttributeParser("on", f.setOn, parseSelectorOn),
 		),
 	)
 }
 
+func decodePageBlock(block *hcl.Block) (*Page, hcl.Diagnostics) {
+	p := new(Page)
+	return reduce(
+		p,
+		block,
+		supportsDeclRange(&p.DeclRange),
+		supportsOptionalLabel(&p.Name, &p.NameRange),
+		supportsPartialContentSchema(
+			pageBlockSchema,
+			withBlock("selector", func(b *hcl.Block) hcl.Diagnostics {
+				cfg, diags := decodeSelectorBlock(b)
+				p.Selectors = append(p.Selectors, cfg)
+				return diags
+			}),
+		),
+	)
+}
+
+// withSelectorAttribute decodes the selector attribute of a task, which is
+// either the target of the selector or a reference to a named selector
+func withSelectorAttribute(target *hcl.Expression, sels *[]*Selector) partialContentMapper {
+	return withSelectorRefAttribute("selector", target, sels)
+}
+
+// withSelectorRefAttribute decodes an attribute which, like the selector
+// attribute of a task, is either a target or a reference to a named selector
+func withSelectorRefAttribute(name string, target *hcl.Expression, sels *[]*Selector) partialContentMapper {
+	return withAttr(name, func(attr *hcl.Attribute) hcl.Diagnostics {
+		if ref, ok := selectorRef(attr.Expr); ok {
+			*sels = append(*sels, &Selector{
+				DeclRange: attr.Expr.Range(),
+				Ref:       ref,
+			})
+			return nil
+		}
+		*target = attr.Expr
+		return nil
+	})
+}
+
+func selectorRef(expr hcl.Expression) (string, bool) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return "", false
+	}
+
+	names := make([]string, 0, len(traversal))
+	for _, t := range traversal {
+		switch step := t.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		default:
+			return "", false
+		}
+	}
+
+	switch {
+	case names[0] == "selector" && len(names) == 2,
+		names[0] == "page" && len(names) == 3:
+		return strings.Join(names, "."), true
+	}
+	return "", false
+}
+
+// ResolveSelectors copies the attributes of the named selectors declared in
+// the files into the selectors of tasks which refer to them. References to
+// selectors which have not been declared are reported.
+func ResolveSelectors(files ...*File) hcl.Diagnostics {
+	var (
+		diags hcl.Diagnostics
+		named = map[string]*Selector{}
+	)
+	declare := func(ref string, s *Selector) {
+		if prev, ok := named[ref]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate selector",
+				Detail:   fmt.Sprintf("The selector %s was already declared at %s.", ref, prev.DeclRange),
+				Subject:  &s.DeclRange,
+			})
+			return
+		}
+		named[ref] = s
+	}
+
+	for _, f := range files {
+		for _, s := range f.Selectors {
+			declare("selector."+s.Name, s)
+		}
+		for _, p := range f.Pages {
+			for _, s := range p.Selectors {
+				declare("page."+p.Name+"."+s.Name, s)
+			}
+		}
+	}
+
+	for _, f := range files {
+		for _, a := range f.Automations {
+			WalkTasks(a.Tasks, func(t Task) {
+				for _, s := range TaskSelectors(t) {
+					if s.Ref == "" {
+						continue
+					}
+					def, ok := named[s.Ref]
+					if !ok {
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Reference to undeclared selector",
+							Detail:   fmt.Sprintf("A selector %s has not been declared.", s.Ref),
+							Subject:  &s.DeclRange,
+						})
+						continue
+					}
+					s.Target, s.By, s.On = def.Target, def.By, def.On
+				}
+			})
+		}
+	}
+	return diags
+}
+
 func parseFloat(s string) (float64, error) {
 	return strconv.ParseFloat(s, 64)
 }
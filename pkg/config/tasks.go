@@ -29,6 +29,16 @@ type NavigateBack struct {
 	DeclRange hcl.Range
 }
 
+// Flow runs another automation, which is identified by its name. The name
+// can be package-scoped in the form @PACKAGE/NAME. Args provides the values of
+// the params declared by the automation.
+type Flow struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Args      hcl.Expression
+}
+
 type Title struct {
 	DeclRange hcl.Range
 	NameRange hcl.Range
@@ -39,42 +49,95 @@ type Eval struct {
 	DeclRange hcl.Range
 	NameRange hcl.Range
 	Name      string
-	Script    string
+	Script    hcl.Expression
 }
 
 type InnerHTML struct {
 	DeclRange hcl.Range
 	NameRange hcl.Range
 	Name      string
-	Selector  string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Text captures the text content of the element
+type Text struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Attribute captures the value of the named attribute of the element, which
+// is empty when the element does not have the attribute
+type Attribute struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Attribute string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Value captures the value of the form element
+type Value struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+}
+
+type OuterHTML struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selector  hcl.Expression
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Count captures the number of elements which match the selectors. Unlike
+// other tasks, it does not wait for an element to match unless at_least is
+// specified.
+type Count struct {
+	DeclRange hcl.Range
+	NameRange hcl.Range
+	Name      string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type Blur struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type Clear struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type Click struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type SendKeys struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 	Keys      hcl.Expression
@@ -82,14 +145,14 @@ type SendKeys struct {
 
 type DoubleClick struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type WaitVisible struct {
 	DeclRange hcl.Range
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
@@ -99,20 +162,21 @@ type Screenshot struct {
 	NameRange hcl.Range
 	Name      string
 	Scale     float64
-	Selector  string
+	Selector  hcl.Expression
 	Selectors []*Selector
 	Options   *Options
 }
 
 type Options struct {
 	DeclRange     hcl.Range
-	RetryInterval *time.Duration
-	AtLeast       *int
+	RetryInterval hcl.Expression
+	AtLeast       hcl.Expression
+	Timeout       hcl.Expression
 }
 
 type Sleep struct {
 	DeclRange hcl.Range
-	Duration  time.Duration
+	Duration  hcl.Expression
 }
 
 type Reload struct {
@@ -142,6 +206,12 @@ var (
 
 	titleBlockSchema = &hcl.BodySchema{}
 
+	flowBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "args"},
+		},
+	}
+
 	innerHTMLBlockSchema = &hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
 			{Name: "selector"},
@@ -152,6 +222,57 @@ var (
 		},
 	}
 
+	textBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+
+	attributeBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+			{Name: "attribute", Required: true},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+
+	valueBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+
+	outerHTMLBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+
+	countBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "selector"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "selector"},
+			{Type: "options"},
+		},
+	}
+
 	clickBlockSchema = &hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
 			{Name: "selector"},
@@ -228,6 +349,7 @@ var (
 		Attributes: []hcl.AttributeSchema{
 			{Name: "at_least"},
 			{Name: "retry_interval"},
+			{Name: "timeout"},
 		},
 	}
 
@@ -320,6 +442,20 @@ func decodeTitleBlock(block *hcl.Block) (*Title, hcl.Diagnostics) {
 	)
 }
 
+func decodeFlowBlock(block *hcl.Block) (*Flow, hcl.Diagnostics) {
+	f := new(Flow)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			flowBlockSchema,
+			withAttributeExpression("args", &f.Args),
+		),
+	)
+}
+
 func decodeInnerHTMLBlock(block *hcl.Block) (*InnerHTML, hcl.Diagnostics) {
 	f := new(InnerHTML)
 	return reduceTask(
@@ -329,7 +465,83 @@ func decodeInnerHTMLBlock(block *hcl.Block) (*InnerHTML, hcl.Diagnostics) {
 		supportsOptionalLabel(&f.Name, &f.NameRange),
 		supportsPartialContentSchema(
 			innerHTMLBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+		),
+	)
+}
+
+func decodeTextBlock(block *hcl.Block) (*Text, hcl.Diagnostics) {
+	f := new(Text)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			textBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+		),
+	)
+}
+
+func decodeAttributeBlock(block *hcl.Block) (*Attribute, hcl.Diagnostics) {
+	f := new(Attribute)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			attributeBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			withAttribute("attribute", &f.Attribute),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+		),
+	)
+}
+
+func decodeValueBlock(block *hcl.Block) (*Value, hcl.Diagnostics) {
+	f := new(Value)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			valueBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+		),
+	)
+}
+
+func decodeOuterHTMLBlock(block *hcl.Block) (*OuterHTML, hcl.Diagnostics) {
+	f := new(OuterHTML)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			outerHTMLBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
+			supportsSelectorBlocks(&f.Selectors, &f.Options),
+		),
+	)
+}
+
+func decodeCountBlock(block *hcl.Block) (*Count, hcl.Diagnostics) {
+	f := new(Count)
+	return reduceTask(
+		f,
+		block,
+		supportsDeclRange(&f.DeclRange),
+		supportsOptionalLabel(&f.Name, &f.NameRange),
+		supportsPartialContentSchema(
+			countBlockSchema,
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -344,7 +556,7 @@ func decodeEvalBlock(block *hcl.Block) (*Eval, hcl.Diagnostics) {
 		supportsOptionalLabel(&f.Name, &f.NameRange),
 		supportsPartialContentSchema(
 			evalBlockSchema,
-			withAttribute("script", &f.Script),
+			withAttributeExpression("script", &f.Script),
 		),
 	)
 }
@@ -357,7 +569,7 @@ func decodeClickBlock(block *hcl.Block) (*Click, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			clickBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -371,7 +583,7 @@ func decodeDoubleClickBlock(block *hcl.Block) (*DoubleClick, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			doubleClickBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -385,7 +597,7 @@ func decodeBlurBlock(block *hcl.Block) (*Blur, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			blurBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -399,7 +611,7 @@ func decodeSendKeysBlock(block *hcl.Block) (*SendKeys, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			sendKeysBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			withAttributeExpression("keys", &f.Keys),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
@@ -414,7 +626,7 @@ func decodeClearBlock(block *hcl.Block) (*Clear, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			clearBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -428,7 +640,7 @@ func decodeSleepBlock(block *hcl.Block) (*Sleep, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			sleepBlockSchema,
-			withAttributeParser("duration", f.setDuration, time.ParseDuration),
+			withAttributeExpressionParser("duration", &f.Duration, time.ParseDuration),
 		),
 	)
 }
@@ -441,7 +653,7 @@ func decodeWaitVisibleBlock(block *hcl.Block) (*WaitVisible, hcl.Diagnostics) {
 		supportsDeclRange(&f.DeclRange),
 		supportsPartialContentSchema(
 			waitVisibleBlockSchema,
-			withAttribute("selector", &f.Selector),
+			withSelectorAttribute(&f.Selector, &f.Selectors),
 			supportsSelectorBlocks(&f.Selectors, &f.Options),
 		),
 	)
@@ -456,7 +668,7 @@ func decodeScreenshotBlock(block *hcl.Block) (*Screenshot, hcl.Diagnostics) {
 		supportsOptionalLabel(&s.Name, &s.NameRange),
 		supportsPartialContentSchema(
 			screenshotBlockSchema,
-			withAttribute("selector", &s.Selector),
+			withSelectorAttribute(&s.Selector, &s.Selectors),
 			withAttributeParser("scale", s.setScale, parseFloat),
 			supportsSelectorBlocks(&s.Selectors, &s.Options),
 		),
@@ -483,43 +695,38 @@ func decodeOptionsBlock(block *hcl.Block) (*Options, hcl.Diagnostics) {
 		supportsDeclRange(&s.DeclRange),
 		supportsPartialContentSchema(
 			optionsBlockSchema,
-			withAttributeParser("retry_interval", s.setRetryInterval, time.ParseDuration),
-			withAttributeParser("at_least", s.setAtLeast, strconv.Atoi),
+			withAttributeExpressionParser("retry_interval", &s.RetryInterval, time.ParseDuration),
+			withAttributeExpressionParser("at_least", &s.AtLeast, strconv.Atoi),
+			withAttributeExpressionParser("timeout", &s.Timeout, time.ParseDuration),
 		),
 	)
 }
 
-func (o *Options) setRetryInterval(n time.Duration) {
-	o.RetryInterval = &n
-}
-
-func (o *Options) setAtLeast(n int) {
-	o.AtLeast = &n
-}
-
-func (o *Sleep) setDuration(n time.Duration) {
-	o.Duration = n
-}
-
 func (o *Screenshot) setScale(n float64) {
 	o.Scale = n
 }
 
 func (*Automation) taskSigil()      {}
+func (*Attribute) taskSigil()       {}
 func (*Blur) taskSigil()            {}
 func (*Clear) taskSigil()           {}
 func (*Click) taskSigil()           {}
+func (*Count) taskSigil()           {}
 func (*DoubleClick) taskSigil()     {}
 func (*Eval) taskSigil()            {}
+func (*Flow) taskSigil()            {}
 func (*InnerHTML) taskSigil()       {}
 func (*Navigate) taskSigil()        {}
 func (*NavigateBack) taskSigil()    {}
 func (*NavigateForward) taskSigil() {}
+func (*OuterHTML) taskSigil()       {}
 func (*Reload) taskSigil()          {}
 func (*Screenshot) taskSigil()      {}
 func (*SendKeys) taskSigil()        {}
 func (*Sleep) taskSigil()           {}
 func (*Stop) taskSigil()            {}
+func (*Text) taskSigil()            {}
 func (*Title) taskSigil()           {}
+func (*Value) taskSigil()           {}
 func (*Version) taskSigil()         {}
 func (*WaitVisible) taskSigil()     {}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Variable is an input variable declared at the top level of a file. Its
+// value can be set from the command line or a values file, otherwise the
+// default is used.
+type Variable struct {
+	DeclRange   hcl.Range
+	NameRange   hcl.Range
+	Name        string
+	Description string
+
+	// Type is the type constraint of the variable, which is
+	// cty.DynamicPseudoType when no type was declared.
+	Type    cty.Type
+	Default hcl.Expression
+}
+
+var (
+	variableBlockSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "type"},
+			{Name: "default"},
+			{Name: "description"},
+		},
+	}
+)
+
+func decodeVariableBlock(block *hcl.Block) (*Variable, hcl.Diagnostics) {
+	v := &Variable{
+		Type: cty.DynamicPseudoType,
+	}
+	return reduce(
+		v,
+		block,
+		supportsDeclRange(&v.DeclRange),
+		supportsOptionalLabel(&v.Name, &v.NameRange),
+		supportsPartialContentSchema(
+			variableBlockSchema,
+			withAttributeType("type", &v.Type),
+			withAttributeExpression("default", &v.Default),
+			withAttribute("description", &v.Description),
+		),
+	)
+}
+
+func checkDuplicateVariables(variables []*Variable) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	seen := map[string]*Variable{}
+	for _, v := range variables {
+		if prev, ok := seen[v.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate variable",
+				Detail:   fmt.Sprintf("The variable %q was already declared at %s.", v.Name, prev.DeclRange),
+				Subject:  &v.DeclRange,
+			})
+			continue
+		}
+		seen[v.Name] = v
+	}
+	return diags
+}
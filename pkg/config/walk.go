@@ -0,0 +1,252 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// WalkTasks calls fn for each of the tasks and for each task nested within
+// them, such as the tasks of if and for_each blocks.
+func WalkTasks(tasks []Task, fn func(Task)) {
+	for _, t := range tasks {
+		fn(t)
+
+		switch t := t.(type) {
+		case *If:
+			WalkTasks(t.Tasks, fn)
+			WalkTasks(t.Else, fn)
+		case *ForEach:
+			WalkTasks(t.Tasks, fn)
+		case *Try:
+			WalkTasks(t.Tasks, fn)
+			WalkTasks(t.Catch, fn)
+			WalkTasks(t.Finally, fn)
+		case *Retry:
+			WalkTasks(t.Tasks, fn)
+		case *Timeout:
+			WalkTasks([]Task{t.Task}, fn)
+		}
+	}
+}
+
+// TaskSelectors gets the selectors of a task which targets elements, which
+// is empty for other tasks
+func TaskSelectors(task Task) []*Selector {
+	switch t := task.(type) {
+	case *Assert:
+		return t.Selectors
+	case *Attribute:
+		return t.Selectors
+	case *Blur:
+		return t.Selectors
+	case *Clear:
+		return t.Selectors
+	case *Click:
+		return t.Selectors
+	case *Count:
+		return t.Selectors
+	case *DoubleClick:
+		return t.Selectors
+	case *Extract:
+		sels := slices.Concat(t.Selectors, t.NextSelectors)
+		for _, f := range t.Fields {
+			sels = append(sels, f.Selectors...)
+		}
+		return sels
+	case *ForEach:
+		return t.Selectors
+	case *InnerHTML:
+		return t.Selectors
+	case *OuterHTML:
+		return t.Selectors
+	case *Screenshot:
+		return t.Selectors
+	case *SendKeys:
+		return t.Selectors
+	case *Text:
+		return t.Selectors
+	case *Value:
+		return t.Selectors
+	case *WaitVisible:
+		return t.Selectors
+	}
+	return nil
+}
+
+// TaskExpressions gets the expressions of a task, including those of its
+// selectors and options but not those of nested tasks. Expressions which are
+// not set are omitted.
+func TaskExpressions(task Task) []hcl.Expression {
+	var exprs []hcl.Expression
+	switch t := task.(type) {
+	case *Navigate:
+		exprs = append(exprs, t.URL)
+	case *Flow:
+		exprs = append(exprs, t.Args)
+	case *Eval:
+		exprs = append(exprs, t.Script)
+	case *InnerHTML:
+		exprs = append(exprs, t.Selector)
+	case *OuterHTML:
+		exprs = append(exprs, t.Selector)
+	case *Text:
+		exprs = append(exprs, t.Selector)
+	case *Attribute:
+		exprs = append(exprs, t.Selector)
+	case *Value:
+		exprs = append(exprs, t.Selector)
+	case *Count:
+		exprs = append(exprs, t.Selector)
+	case *Extract:
+		exprs = append(exprs, t.Root, t.Next)
+		for _, f := range t.Fields {
+			exprs = append(exprs, f.Selector)
+		}
+	case *Blur:
+		exprs = append(exprs, t.Selector)
+	case *Clear:
+		exprs = append(exprs, t.Selector)
+	case *Click:
+		exprs = append(exprs, t.Selector)
+	case *SendKeys:
+		exprs = append(exprs, t.Selector, t.Keys)
+	case *DoubleClick:
+		exprs = append(exprs, t.Selector)
+	case *WaitVisible:
+		exprs = append(exprs, t.Selector)
+	case *Screenshot:
+		exprs = append(exprs, t.Selector)
+	case *Sleep:
+		exprs = append(exprs, t.Duration)
+	case *Assert:
+		exprs = append(exprs, t.Condition, t.Title, t.TitleMatches, t.TextContains,
+			t.Count, t.Value, t.Message, t.Selector)
+	case *If:
+		exprs = append(exprs, t.Condition)
+	case *ForEach:
+		exprs = append(exprs, t.Items, t.Selector)
+	}
+
+	for _, s := range TaskSelectors(task) {
+		exprs = append(exprs, s.Target)
+	}
+	if opts := taskOptions(task); opts != nil {
+		exprs = append(exprs, opts.RetryInterval, opts.AtLeast, opts.Timeout)
+	}
+	return slices.DeleteFunc(exprs, func(e hcl.Expression) bool {
+		return e == nil
+	})
+}
+
+// taskOptions gets the options of a task which targets elements
+func taskOptions(task Task) *Options {
+	switch t := task.(type) {
+	case *Assert:
+		return t.Options
+	case *Attribute:
+		return t.Options
+	case *Blur:
+		return t.Options
+	case *Clear:
+		return t.Options
+	case *Click:
+		return t.Options
+	case *Count:
+		return t.Options
+	case *DoubleClick:
+		return t.Options
+	case *Extract:
+		return t.Options
+	case *ForEach:
+		return t.Options
+	case *InnerHTML:
+		return t.Options
+	case *OuterHTML:
+		return t.Options
+	case *Screenshot:
+		return t.Options
+	case *SendKeys:
+		return t.Options
+	case *Text:
+		return t.Options
+	case *Value:
+		return t.Options
+	case *WaitVisible:
+		return t.Options
+	}
+	return nil
+}
+
+// TaskRange gets the range where the task was declared
+func TaskRange(task Task) hcl.Range {
+	switch t := task.(type) {
+	case *Assert:
+		return t.DeclRange
+	case *Attribute:
+		return t.DeclRange
+	case *Automation:
+		return t.DeclRange
+	case *Blur:
+		return t.DeclRange
+	case *Clear:
+		return t.DeclRange
+	case *Click:
+		return t.DeclRange
+	case *Count:
+		return t.DeclRange
+	case *DoubleClick:
+		return t.DeclRange
+	case *Eval:
+		return t.DeclRange
+	case *Extract:
+		return t.DeclRange
+	case *Flow:
+		return t.DeclRange
+	case *ForEach:
+		return t.DeclRange
+	case *If:
+		return t.DeclRange
+	case *InnerHTML:
+		return t.DeclRange
+	case *Navigate:
+		return t.DeclRange
+	case *NavigateBack:
+		return t.DeclRange
+	case *NavigateForward:
+		return t.DeclRange
+	case *OuterHTML:
+		return t.DeclRange
+	case *Reload:
+		return t.DeclRange
+	case *Retry:
+		return t.DeclRange
+	case *Screenshot:
+		return t.DeclRange
+	case *SendKeys:
+		return t.DeclRange
+	case *Sleep:
+		return t.DeclRange
+	case *Stop:
+		return t.DeclRange
+	case *Text:
+		return t.DeclRange
+	case *Timeout:
+		return t.DeclRange
+	case *Title:
+		return t.DeclRange
+	case *Try:
+		return t.DeclRange
+	case *Value:
+		return t.DeclRange
+	case *Version:
+		return t.DeclRange
+	case *WaitVisible:
+		return t.DeclRange
+	}
+	return hcl.Range{}
+}
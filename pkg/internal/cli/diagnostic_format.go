@@ -0,0 +1,52 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// DiagnosticFormat is the format used to write diagnostics
+type DiagnosticFormat int
+
+const (
+	TextFormat DiagnosticFormat = iota
+	JSONFormat
+	SARIFFormat
+)
+
+func (*DiagnosticFormat) Synopsis() string {
+	return "{text|json|sarif}"
+}
+
+func (f *DiagnosticFormat) Set(arg string) error {
+	switch strings.ToLower(arg) {
+	case "text":
+		*f = TextFormat
+	case "json":
+		*f = JSONFormat
+	case "sarif":
+		*f = SARIFFormat
+	default:
+		return fmt.Errorf("invalid value: %q", arg)
+	}
+	return nil
+}
+
+func (f DiagnosticFormat) String() string {
+	switch f {
+	case TextFormat:
+		return "text"
+	case JSONFormat:
+		return "json"
+	case SARIFFormat:
+		return "sarif"
+	}
+	return ""
+}
+
+var _ flag.Value = (*DiagnosticFormat)(nil)
@@ -0,0 +1,35 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	internalcli "github.com/Carbonfrost/autogun/pkg/internal/cli"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiagnosticFormat", func() {
+
+	Describe("Set", func() {
+
+		DescribeTable("examples",
+			func(arg string, expected internalcli.DiagnosticFormat) {
+				actual := new(internalcli.DiagnosticFormat)
+				err := actual.Set(arg)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*actual).To(Equal(expected))
+				Expect(actual.String()).To(Equal(arg))
+			},
+			Entry("text", "text", internalcli.TextFormat),
+			Entry("json", "json", internalcli.JSONFormat),
+			Entry("sarif", "sarif", internalcli.SARIFFormat),
+		)
+
+		It("returns an error for unknown formats", func() {
+			Expect(new(internalcli.DiagnosticFormat).Set("xml")).To(MatchError(`invalid value: "xml"`))
+		})
+	})
+})
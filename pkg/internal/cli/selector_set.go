@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // SelectorSet is a flag.Value proxy for a slice of model.Selector.
@@ -36,7 +37,7 @@ func (s *SelectorSet) Value() []*model.Selector {
 func (s *SelectorSet) Set(arg string) error {
 	for _, text := range strings.Split(arg, ",") {
 		s.Selectors = append(s.Selectors, &model.Selector{
-			Target: text,
+			Target: model.Literal(cty.StringVal(text)),
 			By:     s.by(),
 		})
 	}
@@ -51,7 +52,9 @@ func (s *SelectorSet) Reset() {
 func (s *SelectorSet) String() string {
 	texts := make([]string, len(s.Selectors))
 	for i, sel := range s.Selectors {
-		texts[i] = sel.Target
+		if v, err := sel.Target.Value(nil); err == nil && v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+			texts[i] = v.AsString()
+		}
 	}
 	return strings.Join(texts, ",")
 }
@@ -12,6 +12,7 @@ import (
 	cli "github.com/Carbonfrost/joe-cli"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var _ = Describe("SelectorSet", func() {
@@ -24,7 +25,7 @@ var _ = Describe("SelectorSet", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual.Selectors).To(Equal([]*model.Selector{
-				{Target: "button", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("button")), By: model.ByQueryAll},
 			}))
 		})
 
@@ -34,9 +35,9 @@ var _ = Describe("SelectorSet", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual.Selectors).To(Equal([]*model.Selector{
-				{Target: "button", By: model.ByQueryAll},
-				{Target: "input", By: model.ByQueryAll},
-				{Target: "a", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("button")), By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("input")), By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("a")), By: model.ByQueryAll},
 			}))
 		})
 
@@ -53,8 +54,8 @@ var _ = Describe("SelectorSet", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual.Selectors).To(Equal([]*model.Selector{
-				{Target: "first", By: model.ByID},
-				{Target: "second", By: model.ByID},
+				{Target: model.Literal(cty.StringVal("first")), By: model.ByID},
+				{Target: model.Literal(cty.StringVal("second")), By: model.ByID},
 			}))
 		})
 
@@ -64,8 +65,8 @@ var _ = Describe("SelectorSet", func() {
 			Expect(actual.Set("input")).To(Succeed())
 
 			Expect(actual.Selectors).To(Equal([]*model.Selector{
-				{Target: "button", By: model.ByQueryAll},
-				{Target: "input", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("button")), By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("input")), By: model.ByQueryAll},
 			}))
 		})
 	})
@@ -100,8 +101,8 @@ var _ = Describe("SelectorSet", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(set.Selectors).To(Equal([]*model.Selector{
-				{Target: "button", By: model.ByQueryAll},
-				{Target: "input", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("button")), By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("input")), By: model.ByQueryAll},
 			}))
 		})
 
@@ -125,9 +126,9 @@ var _ = Describe("SelectorSet", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(set.Selectors).To(Equal([]*model.Selector{
-				{Target: "first", By: model.ByID},
-				{Target: "second", By: model.ByID},
-				{Target: "third", By: model.ByID},
+				{Target: model.Literal(cty.StringVal("first")), By: model.ByID},
+				{Target: model.Literal(cty.StringVal("second")), By: model.ByID},
+				{Target: model.Literal(cty.StringVal("third")), By: model.ByID},
 			}))
 		})
 	})
@@ -0,0 +1,188 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// completionContext describes the place in the source where completion was
+// requested
+type completionContext struct {
+	// path contains the types of the blocks which contain the place
+	path []string
+
+	// attr is the name of the attribute when its value is being written
+	attr string
+
+	// inString is set when the value is being written within quotes
+	inString bool
+}
+
+// complete gets the completions at the offset. Blocks and attributes are
+// completed in the body of a block, and the values of the by and on
+// attributes of selectors are completed.
+func complete(doc *document, offset int) []CompletionItem {
+	if doc.syntax == nil {
+		return nil
+	}
+	c, ok := findCompletionContext(doc.filename, doc.text[:offset])
+	if !ok {
+		return nil
+	}
+
+	if c.attr != "" {
+		return completeValue(c)
+	}
+
+	schema := config.BlockSchema(c.path...)
+	if schema == nil {
+		return nil
+	}
+
+	items := []CompletionItem{}
+	for _, a := range schema.Attributes {
+		items = append(items, CompletionItem{
+			Label:         a.Name,
+			Kind:          completionKindField,
+			Detail:        "attribute",
+			Documentation: markdown(attributeDoc(c.path, a.Name)),
+			InsertText:    a.Name + " = ",
+		})
+	}
+	for _, b := range schema.Blocks {
+		insert := b.Type
+		for range b.LabelNames {
+			insert += ` ""`
+		}
+		items = append(items, CompletionItem{
+			Label:         b.Type,
+			Kind:          completionKindModule,
+			Detail:        "block",
+			Documentation: markdown(blockDoc(b.Type)),
+			InsertText:    insert + " {",
+		})
+	}
+	return items
+}
+
+func completeValue(c completionContext) []CompletionItem {
+	if len(c.path) == 0 || c.path[len(c.path)-1] != "selector" {
+		return nil
+	}
+
+	var values []string
+	switch c.attr {
+	case "by":
+		for _, v := range config.SelectorByValues {
+			values = append(values, strings.ToLower(string(v)))
+		}
+	case "on":
+		for _, v := range config.SelectorOnValues {
+			values = append(values, strings.ToLower(string(v)))
+		}
+	default:
+		return nil
+	}
+
+	items := make([]CompletionItem, 0, len(values))
+	for _, v := range values {
+		insert := `"` + v + `"`
+		if c.inString {
+			insert = v
+		}
+		items = append(items, CompletionItem{
+			Label:         v,
+			Kind:          completionKindValue,
+			Detail:        "selector " + c.attr,
+			Documentation: markdown(attributeDoc(c.path, c.attr)),
+			InsertText:    insert,
+		})
+	}
+	return items
+}
+
+// findCompletionContext determines the context from the tokens of the source
+// which precede the place where completion was requested. Tokens are used
+// instead of the syntax tree because the source is usually incomplete while
+// it is being edited.
+func findCompletionContext(filename string, src []byte) (completionContext, bool) {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+
+	var (
+		// stack contains the types of the blocks which are open, or an empty
+		// string for braces which open an object
+		stack []string
+		stmt  []hclsyntax.Token
+	)
+	for _, tok := range tokens {
+		switch tok.Type {
+		case hclsyntax.TokenOBrace:
+			blockType := ""
+			isBlock := len(stmt) > 0 && stmt[0].Type == hclsyntax.TokenIdent &&
+				!slices.Contains(stack, "") &&
+				!slices.ContainsFunc(stmt, isToken(hclsyntax.TokenEqual))
+			if isBlock {
+				blockType = string(stmt[0].Bytes)
+			}
+			stack = append(stack, blockType)
+			stmt = nil
+
+		case hclsyntax.TokenCBrace:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			stmt = nil
+
+		case hclsyntax.TokenNewline, hclsyntax.TokenComment:
+			stmt = nil
+
+		case hclsyntax.TokenEOF:
+
+		default:
+			stmt = append(stmt, tok)
+		}
+	}
+
+	if slices.Contains(stack, "") {
+		return completionContext{}, false
+	}
+	c := completionContext{path: stack}
+
+	switch {
+	case len(stmt) == 0:
+		return c, true
+
+	case len(stmt) == 1 && stmt[0].Type == hclsyntax.TokenIdent && stmt[0].Range.End.Byte == len(src):
+		// The name of an attribute or block is being written
+		return c, true
+
+	case len(stmt) >= 2 && stmt[0].Type == hclsyntax.TokenIdent && stmt[1].Type == hclsyntax.TokenEqual:
+		c.attr = string(stmt[0].Bytes)
+		last := stmt[len(stmt)-1]
+		c.inString = last.Type == hclsyntax.TokenOQuote ||
+			last.Type == hclsyntax.TokenQuotedLit && len(stmt) >= 3 && stmt[len(stmt)-2].Type == hclsyntax.TokenOQuote
+		return c, len(stmt) == 2 || c.inString
+	}
+	return c, false
+}
+
+func isToken(t hclsyntax.TokenType) func(hclsyntax.Token) bool {
+	return func(tok hclsyntax.Token) bool {
+		return tok.Type == t
+	}
+}
+
+func markdown(value string) *MarkupContent {
+	if value == "" {
+		return nil
+	}
+	return &MarkupContent{Kind: markupKindMarkdown, Value: value}
+}
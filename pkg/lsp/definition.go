@@ -0,0 +1,86 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"slices"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model"
+)
+
+// definition finds the declaration of the automation run by a flow or the
+// named selector referenced by any attribute of a task at the offset. The
+// declarations are found within the documents of the workspace.
+func definition(doc *document, workspace []*document, offset int) []Location {
+	var res []Location
+	for _, a := range doc.file.Automations {
+		config.WalkTasks(a.Tasks, func(t config.Task) {
+			if f, ok := t.(*config.Flow); ok && f.NameRange.ContainsOffset(offset) {
+				res = flowDefinition(doc, workspace, f.Name)
+			}
+			for _, s := range config.TaskSelectors(t) {
+				if s.Ref != "" && s.DeclRange.ContainsOffset(offset) {
+					res = selectorDefinition(workspace, s.Ref)
+				}
+			}
+		})
+	}
+	return res
+}
+
+// flowDefinition finds the automation which a flow of the document runs,
+// resolving its name as the driver does
+func flowDefinition(doc *document, workspace []*document, name string) []Location {
+	m := &model.Model{}
+	for _, d := range workspace {
+		pkg := d.file.Package()
+		if pkg != "" && !slices.Contains(m.Packages, pkg) {
+			m.Packages = append(m.Packages, pkg)
+		}
+		for _, a := range d.file.Automations {
+			m.Automations = append(m.Automations, &model.Automation{Name: a.Name, Package: pkg})
+		}
+	}
+
+	target, err := m.ResolveAutomation(name, doc.file.Package())
+	if err != nil {
+		return nil
+	}
+
+	var res []Location
+	for _, d := range workspace {
+		if d.file.Package() != target.Package {
+			continue
+		}
+		for _, a := range d.file.Automations {
+			if a.Name == target.Name {
+				res = append(res, Location{URI: d.uri, Range: d.lspRange(a.NameRange)})
+			}
+		}
+	}
+	return res
+}
+
+// selectorDefinition finds the named selector of the reference, which has the
+// form selector.NAME or page.PAGE.NAME
+func selectorDefinition(workspace []*document, ref string) []Location {
+	var res []Location
+	for _, d := range workspace {
+		for _, s := range d.file.Selectors {
+			if ref == "selector."+s.Name {
+				res = append(res, Location{URI: d.uri, Range: d.lspRange(s.NameRange)})
+			}
+		}
+		for _, p := range d.file.Pages {
+			for _, s := range p.Selectors {
+				if ref == "page."+p.Name+"."+s.Name {
+					res = append(res, Location{URI: d.uri, Range: d.lspRange(s.NameRange)})
+				}
+			}
+		}
+	}
+	return res
+}
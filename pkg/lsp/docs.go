@@ -0,0 +1,172 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+// blockDocs describes each type of block
+var blockDocs = map[string]string{
+	"automation":       "A named sequence of tasks. It can be run from the command line or by a `flow` task of another automation.",
+	"variable":         "A value which is provided when automations run, such as by `--var` or a values file. It is referenced as `var.NAME`.",
+	"locals":           "Values which are computed from expressions and referenced as `local.NAME`.",
+	"import":           "Imports the automations of a package, which are referenced as `@NAME/AUTOMATION`.",
+	"selector":         "Identifies the elements which a task targets. A named selector is declared at the top level or in a `page` and referenced as `selector.NAME` or `page.PAGE.NAME`.",
+	"page":             "Groups the named selectors of a page, which are referenced as `page.PAGE.NAME`.",
+	"browser":          "A profile which configures how the browser is started or connected. The profile named `default` is used unless `--profile` selects another.",
+	"device":           "A custom device to emulate, which can be selected by name in the same manner as the built-in devices.",
+	"lint":             "Configures the rules which are checked by `autogun check --lint`.",
+	"format":           "Configures the canonical style which is applied by `autogun fmt`.",
+	"output":           "A value which is written to the result of the automation. When an automation declares outputs, only these values appear in the result.",
+	"options":          "Options which control how a task waits for the elements that it targets.",
+	"else":             "The tasks which run when the condition of the `if` block is false.",
+	"catch":            "The tasks which run when a task of the `try` block fails. The error is referenced as `error`.",
+	"finally":          "The tasks which always run after the tasks of the `try` block.",
+	"assert":           "Fails the automation unless the page satisfies the condition.",
+	"blur":             "Removes focus from the element.",
+	"clear":            "Clears the value of the input element.",
+	"click":            "Clicks the element.",
+	"double_click":     "Double-clicks the element.",
+	"eval":             "Evaluates a script in the page and captures its result under the name of the block.",
+	"flow":             "Runs the named automation, passing its params as `args`. The values that it returns are captured.",
+	"inner_html":       "Captures the inner HTML of the element under the name of the block.",
+	"outer_html":       "Captures the outer HTML of the element under the name of the block.",
+	"text":             "Captures the text content of the element under the name of the block.",
+	"attribute":        "Captures the value of an attribute of the element under the name of the block. The value is null when the element does not have the attribute.",
+	"value":            "Captures the value of the form element under the name of the block.",
+	"count":            "Captures the number of elements which match the selector under the name of the block. Unless `at_least` is specified, no element needs to match.",
+	"extract":          "Captures a list of objects under the name of the block, one for each element matched by `root`. Each `field` block provides an attribute of the objects. When `next` is specified, its element is clicked to load more pages.",
+	"field":            "An attribute of the objects captured by `extract`, read from the first element within the root which matches the selector. The attribute is null when no element matches.",
+	"navigate":         "Navigates to the URL.",
+	"navigate_back":    "Navigates back in the history of the browser.",
+	"navigate_forward": "Navigates forward in the history of the browser.",
+	"reload":           "Reloads the page.",
+	"screenshot":       "Captures a screenshot of the page or of the element, which is saved in a file with the name of the block.",
+	"send_keys":        "Sends keys to the element.",
+	"sleep":            "Waits for a fixed duration.",
+	"stop":             "Stops loading the page.",
+	"title":            "Captures the title of the page under the name of the block.",
+	"version":          "Prints the version of the browser.",
+	"wait_visible":     "Waits until the element is visible.",
+	"if":               "Runs its tasks when the condition is true, otherwise the tasks in its `else` block.",
+	"for_each":         "Runs its tasks once for each item or for each element matched by its selector. The item is referenced as `each.value` and the values captured by each iteration are collected under the name of the block. Values captured by its tasks are not in scope after the block.",
+	"try":              "Runs its tasks and handles any error using the tasks in its `catch` block.",
+	"retry":            "Runs its tasks again when they fail, up to the number of attempts.",
+}
+
+// attributeDocs describes the attributes of each type of block, keyed by
+// BLOCK.ATTRIBUTE. Attributes allowed on any task are keyed by the name of
+// the attribute alone.
+var attributeDocs = map[string]string{
+	"when":    "Runs the task only when the condition is true.",
+	"timeout": "The longest duration that the task can take, such as `\"30s\"`.",
+
+	"automation.timeout": "The longest duration that the automation can take, such as `\"5m\"`.",
+	"automation.params":  "The names of the params which a flow passes as its `args`, which are referenced as `param.NAME`.",
+	"automation.returns": "An object whose values are captured by the flow which runs the automation.",
+
+	"variable.type":        "The type constraint of the value.",
+	"variable.default":     "The value used when none is provided.",
+	"variable.description": "Describes the variable.",
+
+	"import.source": "The directory or file which contains the package, relative to the file of the import.",
+
+	"selector.target": "The CSS selector, XPath, text or JavaScript path which identifies the element.",
+	"selector.by":     "How the target is interpreted: `search` (the default), `query`, `query_all`, `id` or `js_path`.",
+	"selector.on":     "The state that the element must be in: `ready`, `visible`, `not_visible`, `enabled`, `selected` or `not_present`.",
+
+	"browser.exec_path":          "The path of the browser executable.",
+	"browser.remote_url":         "The URL of a browser to connect to instead of starting one.",
+	"browser.env":                "Environment variables of the browser process.",
+	"browser.flags":              "Command line flags of the browser. A bool enables or suppresses a flag.",
+	"browser.window_size":        "The size of the window as `[WIDTH, HEIGHT]`.",
+	"browser.ws_url_timeout":     "How long to wait for the browser to provide its WebSocket URL.",
+	"browser.user_data_dir":      "The directory where the browser stores its profile.",
+	"browser.proxy_server":       "The proxy server which the browser uses.",
+	"browser.user_agent":         "The user agent which the browser reports.",
+	"browser.headless":           "Whether the browser runs without a window.",
+	"browser.no_sandbox":         "Whether the sandbox of the browser is disabled.",
+	"browser.disable_gpu":        "Whether the GPU of the browser is disabled.",
+	"browser.ignore_cert_errors": "Whether certificate errors are ignored.",
+	"browser.no_modify_url":      "Whether the remote URL is used as given.",
+
+	"device.description": "Describes the device.",
+	"device.user_agent":  "The user agent which the device reports.",
+	"device.width":       "The width of the screen in pixels.",
+	"device.height":      "The height of the screen in pixels.",
+	"device.scale":       "The device scale factor.",
+	"device.landscape":   "Whether the device is in landscape orientation.",
+	"device.mobile":      "Whether the device is mobile.",
+	"device.touch":       "Whether the device has a touch screen.",
+
+	"lint.rules": "An object which enables or disables each rule by name.",
+
+	"format.enum_case":      "The casing of the values of `by` and `on`: `upper` (the default) or `lower`.",
+	"format.selector_style": "How a selector which has only a target is written: `attribute` or `block`. By default, selectors are kept as written.",
+
+	"output.value":     "The value which is written to the result.",
+	"output.sensitive": "Whether the value is redacted from the result.",
+
+	"options.at_least":       "The number of elements which must match.",
+	"options.retry_interval": "How often to check for the elements.",
+	"options.timeout":        "How long to wait for the elements.",
+
+	"assert.condition":     "An expression which must be true.",
+	"assert.title":         "The title which the page must have.",
+	"assert.title_matches": "A regular expression which the title of the page must match.",
+	"assert.text_contains": "Text which the element must contain.",
+	"assert.count":         "The number of elements which must match the selector.",
+	"assert.attribute":     "The name of the attribute of the element which must have the value.",
+	"assert.value":         "The value which the attribute of the element must have.",
+	"assert.message":       "The message of the error when the assertion fails.",
+	"assert.selector":      "The target of the element, or a reference to a named selector.",
+
+	"blur.selector":         "The target of the element, or a reference to a named selector.",
+	"clear.selector":        "The target of the element, or a reference to a named selector.",
+	"click.selector":        "The target of the element, or a reference to a named selector.",
+	"double_click.selector": "The target of the element, or a reference to a named selector.",
+	"inner_html.selector":   "The target of the element, or a reference to a named selector.",
+	"outer_html.selector":   "The target of the element, or a reference to a named selector.",
+	"text.selector":         "The target of the element, or a reference to a named selector.",
+	"attribute.selector":    "The target of the element, or a reference to a named selector.",
+	"attribute.attribute":   "The name of the attribute to capture.",
+	"value.selector":        "The target of the element, or a reference to a named selector.",
+	"count.selector":        "The target of the elements to count, or a reference to a named selector.",
+	"extract.root":          "The target of the elements which provide the objects, or a reference to a named selector.",
+	"extract.next":          "The target of the element which is clicked to load the next page, or a reference to a named selector. Extraction stops when no element matches.",
+	"extract.max_pages":     "The largest number of pages to extract. The default is 10.",
+	"field.selector":        "The target of the element within the root, or a reference to a named selector.",
+	"field.attribute":       "The name of the attribute to capture instead of the text of the element.",
+	"field.type":            "The type of the attribute: `string` (the default), `number` or `bool`.",
+	"wait_visible.selector": "The target of the element, or a reference to a named selector.",
+	"screenshot.selector":   "The target of the element, or a reference to a named selector.",
+	"screenshot.scale":      "The scale of the screenshot of the element.",
+	"send_keys.selector":    "The target of the element, or a reference to a named selector.",
+	"send_keys.keys":        "The keys to send.",
+	"eval.script":           "The script to evaluate.",
+	"flow.args":             "An object which provides the params of the automation.",
+	"navigate.url":          "The URL to navigate to.",
+	"sleep.duration":        "The duration to wait, such as `\"1s\"`.",
+
+	"if.condition":      "The expression which determines whether the tasks run.",
+	"for_each.items":    "The list or map whose items are iterated.",
+	"for_each.selector": "The target of the elements which are iterated, or a reference to a named selector.",
+	"retry.attempts":    "The number of attempts.",
+	"retry.backoff":     "The delay before the second attempt.",
+	"retry.multiplier":  "The factor which scales the delay after each attempt.",
+}
+
+func blockDoc(blockType string) string {
+	return blockDocs[blockType]
+}
+
+// attributeDoc gets the description of an attribute of the innermost block
+// of the path
+func attributeDoc(path []string, name string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	if doc, ok := attributeDocs[path[len(path)-1]+"."+name]; ok {
+		return doc
+	}
+	return attributeDocs[name]
+}
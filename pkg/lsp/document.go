@@ -0,0 +1,145 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// document is the source of a file which has been decoded. The source either
+// comes from the client when the document is open, or is read from disk.
+type document struct {
+	uri      string
+	filename string
+	text     []byte
+
+	file  *config.File
+	diags hcl.Diagnostics
+
+	// syntax is the parsed source, which is nil for JSON files
+	syntax *hcl.File
+}
+
+func newDocument(uri string, text []byte) *document {
+	filename := uriFilename(uri)
+	file, diags := config.NewParser(nil).ParseSource(filename, text)
+	d := &document{
+		uri:      uri,
+		filename: filename,
+		text:     text,
+		file:     file,
+		diags:    diags,
+	}
+	if !isJSON(filename) {
+		d.syntax = parseSyntax(filename, text)
+	}
+	return d
+}
+
+// loadDocument reads the document from disk
+func loadDocument(filename string) (*document, error) {
+	text, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return newDocument(filenameURI(filename), text), nil
+}
+
+// offset converts the position to a byte offset within the text
+func (d *document) offset(p Position) int {
+	i := 0
+	for line := 0; line < p.Line && i < len(d.text); i++ {
+		if d.text[i] == '\n' {
+			line++
+		}
+	}
+
+	for units := 0; i < len(d.text) && d.text[i] != '\n' && units < p.Character; {
+		r, size := utf8.DecodeRune(d.text[i:])
+		units += utf16.RuneLen(r)
+		i += size
+	}
+	return i
+}
+
+// position converts the byte offset within the text to a position
+func (d *document) position(offset int) Position {
+	offset = min(offset, len(d.text))
+
+	var p Position
+	lineStart := 0
+	for i := 0; i < offset; i++ {
+		if d.text[i] == '\n' {
+			p.Line++
+			lineStart = i + 1
+		}
+	}
+	for i := lineStart; i < offset; {
+		r, size := utf8.DecodeRune(d.text[i:])
+		p.Character += utf16.RuneLen(r)
+		i += size
+	}
+	return p
+}
+
+func (d *document) lspRange(r hcl.Range) Range {
+	return Range{
+		Start: d.position(r.Start.Byte),
+		End:   d.position(r.End.Byte),
+	}
+}
+
+// automationFileSuffixes contains the suffixes of files which contain
+// automations
+var automationFileSuffixes = []string{
+	".autog",
+	".autogun",
+	".hcl",
+	".autog.json",
+	".autogun.json",
+	".hcl.json",
+}
+
+func isAutomationFile(name string) bool {
+	for _, suffix := range automationFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSON(name string) bool {
+	return strings.HasSuffix(name, ".json")
+}
+
+// uriFilename gets the filename of a file URI. Other URIs are used as the
+// filename unchanged.
+func uriFilename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func filenameURI(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	u := url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(filename),
+	}
+	return u.String()
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// hover gets the documentation of the block type or attribute name at the
+// offset, which is nil when there is none
+func hover(doc *document, offset int) *Hover {
+	n := findNode(doc.syntax, offset)
+
+	var (
+		text    string
+		subject hcl.Range
+	)
+	switch {
+	case n.block != nil && n.label < 0:
+		text = blockHover(n.block.Type, n.path[:len(n.path)-1])
+		subject = n.block.TypeRange
+	case n.attr != nil && containsOffset(n.attr.NameRange, offset):
+		if doc := attributeDoc(n.path, n.attr.Name); doc != "" {
+			text = fmt.Sprintf("**%s** (attribute)\n\n%s", n.attr.Name, doc)
+		}
+		subject = n.attr.NameRange
+	}
+	if text == "" {
+		return nil
+	}
+
+	r := doc.lspRange(subject)
+	return &Hover{
+		Contents: MarkupContent{Kind: markupKindMarkdown, Value: text},
+		Range:    &r,
+	}
+}
+
+// blockHover describes a block type together with its labels and the
+// attributes and blocks that its body allows
+func blockHover(blockType string, parent []string) string {
+	doc := blockDoc(blockType)
+	if doc == "" {
+		return ""
+	}
+
+	var header hcl.BlockHeaderSchema
+	if schema := config.BlockSchema(parent...); schema != nil {
+		for _, b := range schema.Blocks {
+			if b.Type == blockType {
+				header = b
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**", blockType)
+	for _, l := range header.LabelNames {
+		fmt.Fprintf(&sb, " \"%s\"", strings.ToUpper(l))
+	}
+	fmt.Fprintf(&sb, " (block)\n\n%s", doc)
+
+	if schema := config.BlockSchema(append(parent, blockType)...); schema != nil {
+		if len(schema.Attributes) > 0 {
+			sb.WriteString("\n\nAttributes:")
+			for _, a := range schema.Attributes {
+				fmt.Fprintf(&sb, " `%s`", a.Name)
+			}
+		}
+		if len(schema.Blocks) > 0 {
+			sb.WriteString("\n\nBlocks:")
+			for _, b := range schema.Blocks {
+				fmt.Fprintf(&sb, " `%s`", b.Type)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lsp provides a language server for automation files, which speaks
+// the Language Server Protocol. It offers completion of blocks, attributes and
+// the values of selectors, hover documentation, navigation to automations and
+// named selectors, and diagnostics as documents are edited.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/internal/build"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Server is a language server which handles the messages of one client.
+// Messages are handled in the order they are received.
+type Server struct {
+	conn *conn
+
+	// docs contains the documents which are open in the client
+	docs map[string]*document
+
+	initialized bool
+	shutdown    bool
+}
+
+// ErrExitWithoutShutdown is returned by [Server.Run] when the client asks
+// the server to exit before asking it to shut down
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown request")
+
+// NewServer creates a server which reads messages from r and writes them to w
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		conn: newConn(r, w),
+		docs: map[string]*document{},
+	}
+}
+
+// Run handles messages until the client asks the server to exit or the
+// connection is closed.
+func (s *Server) Run() error {
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rerr *responseError
+			if errors.As(err, &rerr) {
+				if err := s.conn.reply(nil, nil, rerr); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (any, error) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		return &InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync: textDocumentSyncFull,
+				CompletionProvider: &CompletionOptions{
+					TriggerCharacters: []string{"\""},
+				},
+				HoverProvider:      true,
+				DefinitionProvider: true,
+			},
+			ServerInfo: &ServerInfo{
+				Name:    "autogun",
+				Version: build.Version.Version,
+			},
+		}, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		changes := params.ContentChanges
+		return nil, s.update(params.TextDocument.URI, changes[len(changes)-1].Text)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		return &CompletionList{Items: complete(doc, doc.offset(params.Position))}, nil
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		return hover(doc, doc.offset(params.Position)), nil
+
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		return definition(doc, s.workspace(doc), doc.offset(params.Position)), nil
+
+	case "initialized", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+func unmarshalParams(msg *message, params any) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// update replaces the text of the document and publishes its diagnostics
+func (s *Server) update(uri string, text string) error {
+	doc := newDocument(uri, []byte(text))
+	s.docs[uri] = doc
+
+	return s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: s.diagnostics(doc),
+	})
+}
+
+// workspace gets the documents of the directory which contains the document,
+// which are read from disk unless they are open in the client, and the
+// documents of the packages which they import. The document itself is always
+// included.
+func (s *Server) workspace(doc *document) []*document {
+	res := append([]*document{doc}, s.directory(filepath.Dir(doc.filename), doc.filename)...)
+	return append(res, s.imports(res)...)
+}
+
+// directory gets the documents of the automation files in the directory except
+// for the excluded file
+func (s *Server) directory(dir string, exclude string) []*document {
+	var res []*document
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		filename := filepath.Join(dir, e.Name())
+		if e.IsDir() || !isAutomationFile(filename) || filename == exclude {
+			continue
+		}
+		if open, ok := s.docs[filenameURI(filename)]; ok {
+			res = append(res, open)
+			continue
+		}
+		if loaded, err := loadDocument(filename); err == nil {
+			res = append(res, loaded)
+		}
+	}
+	return res
+}
+
+// imports gets the documents of the packages which the documents import and in
+// turn, the packages which they import. Sources of imports are relative to the
+// directory of the document which contains the import. The documents are
+// decoded again so that setting their package does not affect open documents.
+func (s *Server) imports(docs []*document) []*document {
+	var (
+		res    []*document
+		loaded = map[string]bool{}
+		queue  = slices.Clone(docs)
+	)
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+
+		for _, imp := range d.file.Imports {
+			if loaded[imp.Name] {
+				continue
+			}
+			loaded[imp.Name] = true
+
+			for _, p := range s.packageDocuments(filepath.Join(filepath.Dir(d.filename), imp.Source)) {
+				p.file.SetPackage(imp.Name)
+				res = append(res, p)
+				queue = append(queue, p)
+			}
+		}
+	}
+	return res
+}
+
+// packageDocuments gets the documents of the package at the path, which is
+// either a file or a directory that is searched recursively
+func (s *Server) packageDocuments(path string) []*document {
+	var res []*document
+	_ = filepath.WalkDir(path, func(filename string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() || !isAutomationFile(filename) {
+			return nil
+		}
+		if open, ok := s.docs[filenameURI(filename)]; ok {
+			res = append(res, newDocument(open.uri, open.text))
+			return nil
+		}
+		if loaded, err := loadDocument(filename); err == nil {
+			res = append(res, loaded)
+		}
+		return nil
+	})
+	return res
+}
+
+// diagnostics gets the diagnostics of the document, including references to
+// named selectors which cannot be resolved within its workspace
+func (s *Server) diagnostics(doc *document) []Diagnostic {
+	diags := doc.diags
+	if !diags.HasErrors() {
+		ws := s.workspace(doc)
+		files := make([]*config.File, 0, len(ws))
+		for _, d := range ws {
+			files = append(files, d.file)
+		}
+		for _, d := range config.ResolveSelectors(files...) {
+			if d.Subject != nil && d.Subject.Filename == doc.filename {
+				diags = append(diags, d)
+			}
+		}
+	}
+
+	res := make([]Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+		diag := Diagnostic{
+			Severity: severityError,
+			Source:   "autogun",
+			Message:  msg,
+		}
+		if d.Severity == hcl.DiagWarning {
+			diag.Severity = severityWarning
+		}
+		if d.Subject != nil {
+			diag.Range = doc.lspRange(*d.Subject)
+		}
+		res = append(res, diag)
+	}
+	return res
+}
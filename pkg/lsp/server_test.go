@@ -0,0 +1,326 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/lsp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+// client sends requests to a server and reads its responses
+type client struct {
+	w      io.WriteCloser
+	r      *textproto.Reader
+	nextID int
+	done   chan error
+}
+
+type response struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newClient() *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	c := &client{
+		w:    clientOut,
+		r:    textproto.NewReader(bufio.NewReader(clientIn)),
+		done: make(chan error, 1),
+	}
+	go func() {
+		c.done <- lsp.NewServer(serverIn, serverOut).Run()
+		serverOut.Close()
+	}()
+	return c
+}
+
+func (c *client) send(method string, params any, request bool) {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if request {
+		c.nextID++
+		msg["id"] = c.nextID
+	}
+	data, err := json.Marshal(msg)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// readRaw reads the content of the next message
+func (c *client) readRaw() []byte {
+	header, err := c.r.ReadMIMEHeader()
+	Expect(err).NotTo(HaveOccurred())
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	Expect(err).NotTo(HaveOccurred())
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(c.r.R, data)
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
+
+func (c *client) read() *response {
+	var res response
+	Expect(json.Unmarshal(c.readRaw(), &res)).To(Succeed())
+	return &res
+}
+
+// request sends a request and decodes the result of its response
+func (c *client) request(method string, params any, result any) {
+	c.send(method, params, true)
+	res := c.read()
+	Expect(res.Error).To(BeNil())
+	Expect(json.Unmarshal(res.Result, result)).To(Succeed())
+}
+
+func (c *client) open(uri string, text string) lsp.PublishDiagnosticsParams {
+	c.send("textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "autogun", Text: text},
+	}, false)
+
+	var diags lsp.PublishDiagnosticsParams
+	res := c.read()
+	Expect(res.Method).To(Equal("textDocument/publishDiagnostics"))
+	Expect(json.Unmarshal(res.Params, &diags)).To(Succeed())
+	return diags
+}
+
+func at(uri string, line, character int) lsp.TextDocumentPositionParams {
+	return lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func labels(items []lsp.CompletionItem) []string {
+	res := make([]string, 0, len(items))
+	for _, i := range items {
+		res = append(res, i.Label)
+	}
+	return res
+}
+
+var _ = Describe("Server", func() {
+
+	var (
+		c   *client
+		dir string
+		uri string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		uri = "file://" + filepath.ToSlash(filepath.Join(dir, "main.autog"))
+
+		c = newClient()
+		var init lsp.InitializeResult
+		c.request("initialize", map[string]any{}, &init)
+		Expect(init.Capabilities.HoverProvider).To(BeTrue())
+		c.send("initialized", map[string]any{}, false)
+	})
+
+	AfterEach(func() {
+		var result any
+		c.request("shutdown", nil, &result)
+		c.send("exit", nil, false)
+		Eventually(c.done).Should(Receive(BeNil()))
+	})
+
+	It("publishes diagnostics of the parser", func() {
+		diags := c.open(uri, "automation \"main\" {\n  clik {}\n}\n")
+		Expect(diags.URI).To(Equal(uri))
+		Expect(diags.Diagnostics).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Message":  HavePrefix("Unsupported block type"),
+			"Severity": Equal(1),
+			"Range": Equal(lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 2},
+				End:   lsp.Position{Line: 1, Character: 6},
+			}),
+		})))
+	})
+
+	It("publishes diagnostics for unresolved selectors", func() {
+		diags := c.open(uri, "automation \"main\" {\n  click {\n    selector = selector.missing\n  }\n}\n")
+		Expect(diags.Diagnostics).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Message": HavePrefix("Reference to undeclared selector"),
+		})))
+	})
+
+	DescribeTable("completion",
+		func(text string, line, character int, expected []string) {
+			c.open(uri, text)
+			var list lsp.CompletionList
+			c.request("textDocument/completion", at(uri, line, character), &list)
+			Expect(labels(list.Items)).To(ContainElements(expected))
+		},
+		Entry("top-level blocks", "\n", 0, 0, []string{"automation", "variable", "selector", "page"}),
+		Entry("task blocks", "automation \"main\" {\n  cl\n}\n", 1, 4, []string{"click", "clear", "timeout", "output"}),
+		Entry("attributes of tasks", "automation \"main\" {\n  click {\n    \n  }\n}\n", 2, 4, []string{"selector", "when", "timeout", "options"}),
+		Entry("by values", "selector \"a\" {\n  by = \n}\n", 1, 7, []string{"query", "search", "js_path"}),
+		Entry("on values in quotes", "selector \"a\" {\n  on = \"\n}\n", 1, 8, []string{"visible", "not_present"}),
+	)
+
+	It("does not complete within expressions", func() {
+		c.open(uri, "automation \"main\" {\n  returns = {\n    \n  }\n}\n")
+		var list lsp.CompletionList
+		c.request("textDocument/completion", at(uri, 2, 4), &list)
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	It("describes blocks on hover", func() {
+		c.open(uri, "automation \"main\" {\n  click {\n    selector = \"#a\"\n  }\n}\n")
+		var h lsp.Hover
+		c.request("textDocument/hover", at(uri, 1, 3), &h)
+		Expect(h.Contents.Value).To(HavePrefix("**click** (block)"))
+		Expect(h.Contents.Value).To(ContainSubstring("Clicks the element."))
+	})
+
+	It("describes attributes on hover", func() {
+		c.open(uri, "automation \"main\" {\n  sleep {\n    duration = \"1s\"\n  }\n}\n")
+		var h lsp.Hover
+		c.request("textDocument/hover", at(uri, 2, 6), &h)
+		Expect(h.Contents.Value).To(ContainSubstring("The duration to wait"))
+	})
+
+	It("finds the automation of a flow", func() {
+		other := filepath.Join(dir, "login.autog")
+		Expect(os.WriteFile(other, []byte("automation \"login\" {\n}\n"), 0644)).To(Succeed())
+
+		c.open(uri, "automation \"main\" {\n  flow \"login\" {}\n}\n")
+		var locs []lsp.Location
+		c.request("textDocument/definition", at(uri, 1, 10), &locs)
+		Expect(locs).To(ConsistOf(lsp.Location{
+			URI: "file://" + filepath.ToSlash(other),
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 11},
+				End:   lsp.Position{Line: 0, Character: 18},
+			},
+		}))
+	})
+
+	It("finds named selectors", func() {
+		text := strings.Join([]string{
+			`page "checkout" {`,
+			`  selector "submit" {`,
+			`    target = "#submit"`,
+			`  }`,
+			`}`,
+			`automation "main" {`,
+			`  click {`,
+			`    selector = page.checkout.submit`,
+			`  }`,
+			`}`,
+		}, "\n")
+		c.open(uri, text)
+		var locs []lsp.Location
+		c.request("textDocument/definition", at(uri, 7, 20), &locs)
+		Expect(locs).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"URI":   Equal(uri),
+			"Range": Equal(lsp.Range{Start: lsp.Position{Line: 1, Character: 11}, End: lsp.Position{Line: 1, Character: 19}}),
+		})))
+	})
+
+	It("finds the automations of imported packages", func() {
+		shared := filepath.Join(dir, "shared")
+		Expect(os.MkdirAll(shared, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(shared, "login.autog"), []byte("automation \"login\" {\n}\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "login.autog"), []byte("\nautomation \"login\" {\n}\n"), 0644)).To(Succeed())
+
+		c.open(uri, strings.Join([]string{
+			`import "shared" {`,
+			`  source = "shared"`,
+			`}`,
+			`automation "main" {`,
+			`  flow "@shared/login" {}`,
+			`  flow "login" {}`,
+			`}`,
+		}, "\n"))
+
+		var locs []lsp.Location
+		c.request("textDocument/definition", at(uri, 4, 10), &locs)
+		Expect(locs).To(ConsistOf(lsp.Location{
+			URI: "file://" + filepath.ToSlash(filepath.Join(shared, "login.autog")),
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 11},
+				End:   lsp.Position{Line: 0, Character: 18},
+			},
+		}))
+
+		c.request("textDocument/definition", at(uri, 5, 10), &locs)
+		Expect(locs).To(ConsistOf(lsp.Location{
+			URI: "file://" + filepath.ToSlash(filepath.Join(dir, "login.autog")),
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 11},
+				End:   lsp.Position{Line: 1, Character: 18},
+			},
+		}))
+	})
+
+	DescribeTable("finds named selectors of attributes",
+		func(line, character int) {
+			text := strings.Join([]string{
+				`selector "product" {`,
+				`  target = ".product"`,
+				`}`,
+				`automation "main" {`,
+				`  extract "products" {`,
+				`    root = selector.product`,
+				`    next = selector.product`,
+				`    field "name" {`,
+				`      selector = selector.product`,
+				`    }`,
+				`  }`,
+				`}`,
+			}, "\n")
+			c.open(uri, text)
+			var locs []lsp.Location
+			c.request("textDocument/definition", at(uri, line, character), &locs)
+			Expect(locs).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"URI":   Equal(uri),
+				"Range": Equal(lsp.Range{Start: lsp.Position{Line: 0, Character: 9}, End: lsp.Position{Line: 0, Character: 18}}),
+			})))
+		},
+		Entry("root", 5, 15),
+		Entry("next", 6, 15),
+		Entry("selector of a field", 8, 22),
+	)
+
+	It("replies to messages which cannot be parsed with a null id", func() {
+		_, err := fmt.Fprintf(c.w, "Content-Length: 1\r\n\r\n{")
+		Expect(err).NotTo(HaveOccurred())
+
+		var res map[string]any
+		Expect(json.Unmarshal(c.readRaw(), &res)).To(Succeed())
+		Expect(res).To(HaveKeyWithValue("id", BeNil()))
+		Expect(res).To(HaveKeyWithValue("error", HaveKeyWithValue("code", BeNumerically("==", -32700))))
+	})
+
+	It("reports unknown methods", func() {
+		c.send("workspace/symbol", map[string]any{}, true)
+		res := c.read()
+		Expect(res.Error).NotTo(BeNil())
+		Expect(res.Error.Code).To(Equal(-32601))
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// node identifies the element of the syntax tree at an offset
+type node struct {
+	// path contains the types of the blocks which contain the offset,
+	// including the block whose header contains it
+	path []string
+
+	// block is the block whose type or label contains the offset
+	block *hclsyntax.Block
+
+	// label is the index of the label of the block which contains the offset,
+	// or -1 when the type of the block contains it
+	label int
+
+	// attr is the attribute which contains the offset
+	attr *hclsyntax.Attribute
+}
+
+func parseSyntax(filename string, text []byte) *hcl.File {
+	file, _ := hclsyntax.ParseConfig(text, filename, hcl.InitialPos)
+	return file
+}
+
+// findNode finds the innermost block header or attribute which contains the
+// offset. Only the path is set when the offset is within the body of a block
+// but not within any of its attributes or blocks.
+func findNode(file *hcl.File, offset int) node {
+	var n node
+	if file == nil {
+		return n
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return n
+	}
+
+	for {
+		for _, attr := range body.Attributes {
+			if containsOffset(attr.SrcRange, offset) {
+				n.attr = attr
+				return n
+			}
+		}
+
+		var inner *hclsyntax.Body
+		for _, b := range body.Blocks {
+			if containsOffset(b.TypeRange, offset) {
+				n.path = append(n.path, b.Type)
+				n.block, n.label = b, -1
+				return n
+			}
+			for i, r := range b.LabelRanges {
+				if containsOffset(r, offset) {
+					n.path = append(n.path, b.Type)
+					n.block, n.label = b, i
+					return n
+				}
+			}
+			if b.OpenBraceRange.End.Byte <= offset && offset <= b.CloseBraceRange.Start.Byte {
+				n.path = append(n.path, b.Type)
+				inner = b.Body
+				break
+			}
+		}
+		if inner == nil {
+			return n
+		}
+		body = inner
+	}
+}
+
+func containsOffset(r hcl.Range, offset int) bool {
+	return r.Start.Byte <= offset && offset <= r.End.Byte
+}
@@ -4,8 +4,51 @@
 
 package model
 
-// Automation is a multi-step automated process.
+import (
+	"strings"
+	"time"
+)
+
+// Automation is a multi-step automated process. When run by a flow, Params
+// names the args that it receives as param.NAME and Returns provides the
+// values that are captured by the caller.
 type Automation struct {
-	Name  string
-	Tasks []Task
+	Name    string
+	Package string
+	Timeout time.Duration
+	Params  []string
+	Returns Expression
+	Outputs []*Output
+	Tasks   []Task
+
+	// Location is the source range where the automation was declared
+	Location string
+}
+
+// Output is a value written to the result of running an automation. The
+// value of a sensitive output is redacted.
+type Output struct {
+	Name      string
+	Value     Expression
+	Sensitive bool
+}
+
+// QualifiedName gets the name of the automation, which is prefixed by its
+// package in the form @PACKAGE/NAME when it belongs to an imported package
+func (a *Automation) QualifiedName() string {
+	if a.Package == "" {
+		return a.Name
+	}
+	return "@" + a.Package + "/" + a.Name
+}
+
+// splitQName splits a name in the form @PACKAGE/NAME into its package and
+// name. Other names have no package.
+func splitQName(qname string) (pkg, name string) {
+	if rest, ok := strings.CutPrefix(qname, "@"); ok {
+		if pkg, name, ok := strings.Cut(rest, "/"); ok {
+			return pkg, name
+		}
+	}
+	return "", qname
 }
@@ -0,0 +1,88 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+)
+
+// Browser is a named profile which configures the browser. Fields are nil
+// when they are not set by the profile.
+type Browser struct {
+	Name string
+
+	ExecPath         *string
+	RemoteURL        *string
+	Env              []string
+	Flags            map[string]any
+	WindowSize       *WindowSize
+	WSURLReadTimeout *time.Duration
+
+	UserDataDir      *string
+	ProxyServer      *string
+	UserAgent        *string
+	Headless         *bool
+	NoSandbox        *bool
+	DisableGPU       *bool
+	IgnoreCertErrors *bool
+	NoModifyURL      *bool
+}
+
+// Browser retrieves the browser profile by name, which returns nil when the
+// profile is not declared
+func (m *Model) Browser(name string) *Browser {
+	for _, b := range m.Browsers {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// ResolveBrowser retrieves the browser profile by name. When the name is
+// empty, the default profile is used if it is declared; otherwise, nil is
+// returned.
+func (m *Model) ResolveBrowser(name string) (*Browser, error) {
+	if name == "" {
+		return m.Browser(config.DefaultBrowserProfile), nil
+	}
+	if b := m.Browser(name); b != nil {
+		return b, nil
+	}
+	return nil, fmt.Errorf("browser profile not found %q", name)
+}
+
+func browserFromConfig(cfg *config.Browser) *Browser {
+	if cfg == nil {
+		return nil
+	}
+	b := &Browser{
+		Name:             cfg.Name,
+		ExecPath:         cfg.ExecPath,
+		RemoteURL:        cfg.RemoteURL,
+		Flags:            cfg.Flags,
+		WSURLReadTimeout: cfg.WSURLReadTimeout,
+		UserDataDir:      cfg.UserDataDir,
+		ProxyServer:      cfg.ProxyServer,
+		UserAgent:        cfg.UserAgent,
+		Headless:         cfg.Headless,
+		NoSandbox:        cfg.NoSandbox,
+		DisableGPU:       cfg.DisableGPU,
+		IgnoreCertErrors: cfg.IgnoreCertErrors,
+		NoModifyURL:      cfg.NoModifyURL,
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Env)) {
+		b.Env = append(b.Env, name+"="+cfg.Env[name])
+	}
+	if len(cfg.WindowSize) == 2 {
+		b.WindowSize = &WindowSize{Width: cfg.WindowSize[0], Height: cfg.WindowSize[1]}
+	}
+	return b
+}
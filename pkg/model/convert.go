@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
 )
 
 func fromConfigFile(file *config.File) []*Automation {
@@ -16,27 +17,108 @@ func fromConfigFile(file *config.File) []*Automation {
 	}
 	autos := make([]*Automation, 0, len(file.Automations))
 	for _, a := range file.Automations {
-		autos = append(autos, FromConfig(a))
+		auto := FromConfig(a)
+		if auto.Package == "" {
+			auto.Package = file.Package()
+		}
+		autos = append(autos, auto)
 	}
 	return autos
 }
 
+func variablesFromConfigFile(file *config.File) []*Variable {
+	if file == nil {
+		return nil
+	}
+	vars := make([]*Variable, 0, len(file.Variables))
+	for _, v := range file.Variables {
+		vars = append(vars, variableFromConfig(v))
+	}
+	return vars
+}
+
+func localsFromConfigFile(file *config.File) []*Local {
+	if file == nil {
+		return nil
+	}
+	locals := make([]*Local, 0, len(file.Locals))
+	for _, l := range file.Locals {
+		locals = append(locals, &Local{
+			Name:  l.Name,
+			Value: ExpressionFromHCL(l.Value),
+		})
+	}
+	return locals
+}
+
+// browsersFromConfigFile gets the browser profiles of the file. Profiles are
+// only taken from the workspace and not from imported packages.
+func browsersFromConfigFile(file *config.File) []*Browser {
+	if file == nil || file.Package() != "" {
+		return nil
+	}
+	browsers := make([]*Browser, 0, len(file.Browsers))
+	for _, b := range file.Browsers {
+		browsers = append(browsers, browserFromConfig(b))
+	}
+	return browsers
+}
+
+// devicesFromConfigFile gets the custom devices of the file. Like browser
+// profiles, devices are only taken from the workspace.
+func devicesFromConfigFile(file *config.File) []Device {
+	if file == nil || file.Package() != "" {
+		return nil
+	}
+	devices := make([]Device, 0, len(file.Devices))
+	for _, d := range file.Devices {
+		devices = append(devices, deviceFromConfig(d))
+	}
+	return devices
+}
+
 // FromConfig converts a configuration automation into its model
 // representation
 func FromConfig(cfg *config.Automation) *Automation {
 	if cfg == nil {
 		return nil
 	}
-	tasks := make([]Task, 0, len(cfg.Tasks))
-	for _, t := range cfg.Tasks {
-		tasks = append(tasks, taskFromConfig(t))
-	}
+	pkg, name := splitQName(cfg.Name)
 	return &Automation{
-		Name:  cfg.Name,
-		Tasks: tasks,
+		Name:     name,
+		Package:  pkg,
+		Timeout:  cfg.Timeout,
+		Params:   cfg.Params,
+		Returns:  ExpressionFromHCL(cfg.Returns),
+		Outputs:  outputsFromConfig(cfg.Outputs),
+		Tasks:    tasksFromConfig(cfg.Tasks),
+		Location: cfg.DeclRange.String(),
 	}
 }
 
+func outputsFromConfig(cfg []*config.Output) []*Output {
+	if len(cfg) == 0 {
+		return nil
+	}
+	outputs := make([]*Output, 0, len(cfg))
+	for _, o := range cfg {
+		outputs = append(outputs, &Output{
+			Name:      o.Name,
+			Value:     ExpressionFromHCL(o.Value),
+			Sensitive: o.Sensitive,
+		})
+	}
+	return outputs
+}
+
+func tasksFromConfig(cfg []config.Task) []Task {
+	tasks := make([]Task, 0, len(cfg))
+	for _, t := range cfg {
+		tasks = append(tasks, taskFromConfig(t))
+	}
+	return tasks
+}
+
 func taskFromConfig(task config.Task) Task {
 	switch t := task.(type) {
 	case *config.Navigate:
@@ -48,16 +130,75 @@ func taskFromConfig(task config.Task) Task {
 		return &NavigateForward{}
 	case *config.NavigateBack:
 		return &NavigateBack{}
+	case *config.Assert:
+		return &Assert{
+			Condition:    ExpressionFromHCL(t.Condition),
+			Title:        ExpressionFromHCL(t.Title),
+			TitleMatches: ExpressionFromHCL(t.TitleMatches),
+			TextContains: ExpressionFromHCL(t.TextContains),
+			Count:        ExpressionFromHCL(t.Count),
+			Attribute:    t.Attribute,
+			Value:        ExpressionFromHCL(t.Value),
+			Message:      ExpressionFromHCL(t.Message),
+			Selectors:    selectorsFromConfig(t.Selector, t.Selectors),
+			Options:      optionsFromConfig(t.Options),
+			DeclRange:    t.DeclRange,
+		}
+	case *config.Flow:
+		return &Flow{
+			Name: t.Name,
+			Args: ExpressionFromHCL(t.Args),
+		}
 	case *config.Title:
 		return &Title{Name: t.Name}
 	case *config.Eval:
-		return &Eval{Name: t.Name, Script: t.Script}
+		return &Eval{Name: t.Name, Script: ExpressionFromHCL(t.Script)}
 	case *config.InnerHTML:
 		return &InnerHTML{
 			Name:      t.Name,
 			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
 			Options:   optionsFromConfig(t.Options),
 		}
+	case *config.OuterHTML:
+		return &OuterHTML{
+			Name:      t.Name,
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+		}
+	case *config.Text:
+		return &Text{
+			Name:      t.Name,
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+		}
+	case *config.Attribute:
+		return &Attribute{
+			Name:      t.Name,
+			Attribute: t.Attribute,
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+		}
+	case *config.Value:
+		return &Value{
+			Name:      t.Name,
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+		}
+	case *config.Count:
+		return &Count{
+			Name:      t.Name,
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+		}
+	case *config.Extract:
+		return &Extract{
+			Name:      t.Name,
+			Selectors: selectorsFromConfig(t.Root, t.Selectors),
+			Next:      selectorsFromConfig(t.Next, t.NextSelectors),
+			MaxPages:  t.MaxPages,
+			Fields:    fieldsFromConfig(t.Fields),
+			Options:   optionsFromConfig(t.Options),
+		}
 	case *config.Blur:
 		return &Blur{
 			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
@@ -97,26 +238,61 @@ func taskFromConfig(task config.Task) Task {
 			Options:   optionsFromConfig(t.Options),
 		}
 	case *config.Sleep:
-		return &Sleep{Duration: t.Duration}
+		return &Sleep{Duration: ExpressionFromHCL(t.Duration)}
 	case *config.Reload:
 		return &Reload{}
 	case *config.Stop:
 		return &Stop{}
 	case *config.Version:
 		return &Version{}
+	case *config.ForEach:
+		return &ForEach{
+			Name:      t.Name,
+			Items:     ExpressionFromHCL(t.Items),
+			Selectors: selectorsFromConfig(t.Selector, t.Selectors),
+			Options:   optionsFromConfig(t.Options),
+			Tasks:     tasksFromConfig(t.Tasks),
+		}
+	case *config.If:
+		return &If{
+			Condition: ExpressionFromHCL(t.Condition),
+			Tasks:     tasksFromConfig(t.Tasks),
+			Else:      tasksFromConfig(t.Else),
+		}
+	case *config.Retry:
+		return &Retry{
+			Attempts:   t.Attempts,
+			Backoff:    t.Backoff,
+			Multiplier: t.Multiplier,
+			Tasks:      tasksFromConfig(t.Tasks),
+			Location:   t.DeclRange.String(),
+		}
+	case *config.Timeout:
+		return &Timeout{
+			Duration:  t.Duration,
+			Task:      taskFromConfig(t.Task),
+			BlockType: t.BlockType,
+			Location:  t.DeclRange.String(),
+		}
+	case *config.Try:
+		return &Try{
+			Tasks:   tasksFromConfig(t.Tasks),
+			Catch:   tasksFromConfig(t.Catch),
+			Finally: tasksFromConfig(t.Finally),
+		}
 	default:
 		panic(fmt.Errorf("unexpected task type %T", t))
 	}
 }
 
-func selectorsFromConfig(selector string, sels []*config.Selector) []*Selector {
+func selectorsFromConfig(selector hcl.Expression, sels []*config.Selector) []*Selector {
 	out := make([]*Selector, 0, len(sels)+1)
 	for _, s := range sels {
 		out = append(out, selectorFromConfig(s))
 	}
-	if selector != "" {
+	if selector != nil {
 		out = append(out, &Selector{
-			Target: selector,
+			Target: ExpressionFromHCL(selector),
 			By:     BySearch,
 		})
 	}
@@ -126,12 +302,25 @@ func selectorsFromConfig(selector string, sels []*config.Selector) []*Selector {
 	return out
 }
 
+func fieldsFromConfig(fields []*config.ExtractField) []*ExtractField {
+	out := make([]*ExtractField, len(fields))
+	for i, f := range fields {
+		out[i] = &ExtractField{
+			Name:      f.Name,
+			Selectors: selectorsFromConfig(f.Selector, f.Selectors),
+			Attribute: f.Attribute,
+			Type:      f.Type,
+		}
+	}
+	return out
+}
+
 func selectorFromConfig(s *config.Selector) *Selector {
 	if s == nil {
 		return nil
 	}
 	return &Selector{
-		Target: s.Target,
+		Target: ExpressionFromHCL(s.Target),
 		By:     SelectorBy(s.By),
 		On:     SelectorOn(s.On),
 	}
@@ -142,7 +331,8 @@ func optionsFromConfig(o *config.Options) *Options {
 		return nil
 	}
 	return &Options{
-		RetryInterval: o.RetryInterval,
-		AtLeast:       o.AtLeast,
+		RetryInterval: ExpressionFromHCL(o.RetryInterval),
+		AtLeast:       ExpressionFromHCL(o.AtLeast),
+		Timeout:       ExpressionFromHCL(o.Timeout),
 	}
 }
@@ -25,21 +25,34 @@ var _ = Describe("FromConfig", func() {
 				Expect(out.Tasks).To(HaveLen(1))
 				Expect(out.Tasks[0]).To(BeAssignableToTypeOf(expected))
 			},
+			Entry("assert", new(config.Assert), new(model.Assert)),
+			Entry("attribute", new(config.Attribute), new(model.Attribute)),
 			Entry("blur", new(config.Blur), new(model.Blur)),
 			Entry("clear", new(config.Clear), new(model.Clear)),
 			Entry("click", new(config.Click), new(model.Click)),
+			Entry("count", new(config.Count), new(model.Count)),
 			Entry("double_click", new(config.DoubleClick), new(model.DoubleClick)),
 			Entry("eval", new(config.Eval), new(model.Eval)),
+			Entry("extract", new(config.Extract), new(model.Extract)),
+			Entry("flow", new(config.Flow), new(model.Flow)),
+			Entry("for_each", new(config.ForEach), new(model.ForEach)),
+			Entry("if", new(config.If), new(model.If)),
 			Entry("inner_html", new(config.InnerHTML), new(model.InnerHTML)),
 			Entry("navigate", new(config.Navigate), new(model.Navigate)),
 			Entry("navigate_back", new(config.NavigateBack), new(model.NavigateBack)),
 			Entry("navigate_forward", new(config.NavigateForward), new(model.NavigateForward)),
+			Entry("outer_html", new(config.OuterHTML), new(model.OuterHTML)),
 			Entry("reload", new(config.Reload), new(model.Reload)),
+			Entry("retry", new(config.Retry), new(model.Retry)),
 			Entry("screenshot", new(config.Screenshot), new(model.Screenshot)),
 			Entry("send_keys", new(config.SendKeys), new(model.SendKeys)),
 			Entry("sleep", new(config.Sleep), new(model.Sleep)),
 			Entry("stop", new(config.Stop), new(model.Stop)),
+			Entry("text", new(config.Text), new(model.Text)),
+			Entry("timeout", &config.Timeout{Task: new(config.Click)}, new(model.Timeout)),
 			Entry("title", new(config.Title), new(model.Title)),
+			Entry("try", new(config.Try), new(model.Try)),
+			Entry("value", new(config.Value), new(model.Value)),
 			Entry("version", new(config.Version), new(model.Version)),
 			Entry("wait_visible", new(config.WaitVisible), new(model.WaitVisible)),
 		)
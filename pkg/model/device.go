@@ -6,13 +6,19 @@ package model
 
 import (
 	"cmp"
+	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/Carbonfrost/autogun/pkg/config"
 	"github.com/chromedp/chromedp/device"
 )
 
+// InlineDeviceID is the ID of a device given in the inline form
+const InlineDeviceID = "inline"
+
 var devices map[string]device.Info
 
 func init() {
@@ -98,3 +104,99 @@ func convertDevice(id string, d device.Info) Device {
 		Touch:     d.Touch,
 	}
 }
+
+// LookupDevice looks up a device by ID. Devices declared by the workspace take
+// precedence over the built-in devices.
+func (m *Model) LookupDevice(id string) (Device, bool) {
+	for _, d := range m.Devices {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return LookupDevice(id)
+}
+
+// AllDevices obtains the built-in devices together with the devices declared
+// by the workspace, sorted by ID
+func (m *Model) AllDevices() []Device {
+	res := slices.Clone(m.Devices)
+	for _, d := range Devices() {
+		if !slices.ContainsFunc(m.Devices, func(c Device) bool { return c.ID == d.ID }) {
+			res = append(res, d)
+		}
+	}
+	slices.SortFunc(res, deviceByID)
+	return res
+}
+
+// ParseDevice parses the inline form of a device, which is a comma-separated
+// list of NAME=VALUE settings such as width=412,height=915,mobile. The
+// settings mobile, touch and landscape can be given without a value to
+// enable them. The width and height are required.
+func ParseDevice(spec string) (Device, error) {
+	d := Device{
+		ID:    InlineDeviceID,
+		Name:  "Custom device",
+		Scale: 1,
+	}
+
+	var err error
+	for item := range strings.SplitSeq(spec, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(item), "=")
+		switch name {
+		case "name":
+			d.Name = value
+		case "user_agent":
+			d.UserAgent = value
+		case "width":
+			d.Width, err = strconv.ParseInt(value, 10, 64)
+		case "height":
+			d.Height, err = strconv.ParseInt(value, 10, 64)
+		case "scale":
+			d.Scale, err = strconv.ParseFloat(value, 64)
+		case "mobile", "touch", "landscape":
+			enabled := true
+			if hasValue {
+				enabled, err = strconv.ParseBool(value)
+			}
+			switch name {
+			case "mobile":
+				d.Mobile = enabled
+			case "touch":
+				d.Touch = enabled
+			default:
+				d.Landscape = enabled
+			}
+		default:
+			return Device{}, fmt.Errorf("invalid device %q: unknown setting %q", spec, name)
+		}
+		if err != nil {
+			return Device{}, fmt.Errorf("invalid device %q: %s: %w", spec, name, err)
+		}
+	}
+
+	if d.Width <= 0 || d.Height <= 0 || d.Scale <= 0 {
+		return Device{}, fmt.Errorf("invalid device %q: width, height and scale must be positive", spec)
+	}
+	return d, nil
+}
+
+// IsInlineDevice gets whether the device is given in the inline form
+// rather than by ID
+func IsInlineDevice(s string) bool {
+	return strings.Contains(s, "=")
+}
+
+func deviceFromConfig(cfg *config.Device) Device {
+	return Device{
+		ID:        cfg.Name,
+		Name:      cmp.Or(cfg.Description, cfg.Name),
+		UserAgent: cfg.UserAgent,
+		Width:     cfg.Width,
+		Height:    cfg.Height,
+		Scale:     cfg.Scale,
+		Landscape: cfg.Landscape,
+		Mobile:    cfg.Mobile,
+		Touch:     cfg.Touch,
+	}
+}
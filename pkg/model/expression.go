@@ -17,7 +17,8 @@ type Expression interface {
 	Value(*Scope) (cty.Value, error)
 
 	// Variables enumerates the names of the variables that the expression
-	// references.
+	// references. References to input variables and locals are qualified by
+	// their namespace, as in var.NAME and local.NAME.
 	Variables() []string
 }
 
@@ -37,6 +38,25 @@ func ExpressionFromHCL(expr hcl.Expression) Expression {
 	return hclExpression{expr: expr}
 }
 
+// Literal wraps a value as an [Expression] that always produces the value.
+// It is used for values which are not read from a configuration file, such as
+// those given on the command line.
+func Literal(v cty.Value) Expression {
+	return literalExpression{value: v}
+}
+
+type literalExpression struct {
+	value cty.Value
+}
+
+func (e literalExpression) Value(*Scope) (cty.Value, error) {
+	return e.value, nil
+}
+
+func (e literalExpression) Variables() []string {
+	return nil
+}
+
 type hclExpression struct {
 	expr hcl.Expression
 }
@@ -53,11 +73,36 @@ func (e hclExpression) Variables() []string {
 	traversals := e.expr.Variables()
 	names := make([]string, 0, len(traversals))
 	for _, t := range traversals {
-		names = append(names, t.RootName())
+		names = append(names, variableName(t))
 	}
 	return names
 }
 
+func (e hclExpression) Range() hcl.Range {
+	return e.expr.Range()
+}
+
+func variableName(t hcl.Traversal) string {
+	root := t.RootName()
+	switch root {
+	case "var", "local":
+		if len(t) > 1 {
+			if attr, ok := t[1].(hcl.TraverseAttr); ok {
+				return root + "." + attr.Name
+			}
+		}
+	}
+	return root
+}
+
+// expressionRange gets the source range of the expression, if known
+func expressionRange(e Expression) *hcl.Range {
+	if r, ok := e.(interface{ Range() hcl.Range }); ok {
+		return r.Range().Ptr()
+	}
+	return nil
+}
+
 func (s *Scope) evalContext() *hcl.EvalContext {
 	if s == nil {
 		return &hcl.EvalContext{}
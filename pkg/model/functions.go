@@ -0,0 +1,252 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// Functions gets the functions which can be called from expressions. The
+// functions which read files resolve relative paths against baseDir.
+func Functions(baseDir string) map[string]function.Function {
+	funcs := map[string]function.Function{
+		// Strings
+		"chomp":         stdlib.ChompFunc,
+		"format":        stdlib.FormatFunc,
+		"formatlist":    stdlib.FormatListFunc,
+		"indent":        stdlib.IndentFunc,
+		"join":          stdlib.JoinFunc,
+		"lower":         stdlib.LowerFunc,
+		"regex":         stdlib.RegexFunc,
+		"regexall":      stdlib.RegexAllFunc,
+		"regex_replace": stdlib.RegexReplaceFunc,
+		"replace":       stdlib.ReplaceFunc,
+		"split":         stdlib.SplitFunc,
+		"strlen":        stdlib.StrlenFunc,
+		"substr":        stdlib.SubstrFunc,
+		"title":         stdlib.TitleFunc,
+		"trim":          stdlib.TrimFunc,
+		"trimprefix":    stdlib.TrimPrefixFunc,
+		"trimspace":     stdlib.TrimSpaceFunc,
+		"trimsuffix":    stdlib.TrimSuffixFunc,
+		"upper":         stdlib.UpperFunc,
+
+		// Numbers
+		"abs":      stdlib.AbsoluteFunc,
+		"ceil":     stdlib.CeilFunc,
+		"floor":    stdlib.FloorFunc,
+		"max":      stdlib.MaxFunc,
+		"min":      stdlib.MinFunc,
+		"parseint": stdlib.ParseIntFunc,
+
+		// Collections
+		"coalesce":     stdlib.CoalesceFunc,
+		"coalescelist": stdlib.CoalesceListFunc,
+		"compact":      stdlib.CompactFunc,
+		"concat":       stdlib.ConcatFunc,
+		"contains":     stdlib.ContainsFunc,
+		"distinct":     stdlib.DistinctFunc,
+		"element":      stdlib.ElementFunc,
+		"flatten":      stdlib.FlattenFunc,
+		"index":        stdlib.IndexFunc,
+		"keys":         stdlib.KeysFunc,
+		"length":       stdlib.LengthFunc,
+		"lookup":       stdlib.LookupFunc,
+		"merge":        stdlib.MergeFunc,
+		"range":        stdlib.RangeFunc,
+		"reverse":      stdlib.ReverseListFunc,
+		"slice":        stdlib.SliceFunc,
+		"sort":         stdlib.SortFunc,
+		"values":       stdlib.ValuesFunc,
+		"zipmap":       stdlib.ZipmapFunc,
+
+		// Encoding and conversion
+		"base64decode": base64DecodeFunc,
+		"base64encode": base64EncodeFunc,
+		"csvdecode":    stdlib.CSVDecodeFunc,
+		"jsondecode":   stdlib.JSONDecodeFunc,
+		"jsonencode":   stdlib.JSONEncodeFunc,
+		"tobool":       stdlib.MakeToFunc(cty.Bool),
+		"tonumber":     stdlib.MakeToFunc(cty.Number),
+		"tostring":     stdlib.MakeToFunc(cty.String),
+		"urlencode":    urlEncodeFunc,
+
+		// Time and identifiers
+		"formatdate": stdlib.FormatDateFunc,
+		"timeadd":    stdlib.TimeAddFunc,
+		"timestamp":  timestampFunc,
+		"uuid":       uuidFunc,
+
+		// Environment and files
+		"can":  tryfunc.CanFunc,
+		"env":  envFunc,
+		"file": makeFileFunc(baseDir),
+		"try":  tryfunc.TryFunc,
+	}
+
+	// Templates can call any of the other functions
+	funcs["templatefile"] = makeTemplateFileFunc(baseDir, func() map[string]function.Function {
+		return funcs
+	})
+	return funcs
+}
+
+var base64EncodeFunc = function.New(&function.Spec{
+	Description: "Encodes a string using Base64.",
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.StringVal(base64.StdEncoding.EncodeToString([]byte(args[0].AsString()))), nil
+	},
+})
+
+var base64DecodeFunc = function.New(&function.Spec{
+	Description: "Decodes a Base64 string, which must contain UTF-8 text.",
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		data, err := base64.StdEncoding.DecodeString(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid Base64 data: %v", err)
+		}
+		if !utf8.Valid(data) {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "decoded data is not valid UTF-8")
+		}
+		return cty.StringVal(string(data)), nil
+	},
+})
+
+var urlEncodeFunc = function.New(&function.Spec{
+	Description: "Escapes a string so that it can be used in a URL query.",
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.StringVal(url.QueryEscape(args[0].AsString())), nil
+	},
+})
+
+var timestampFunc = function.New(&function.Spec{
+	Description: "Gets the current time in UTC as an RFC 3339 string.",
+	Params:      []function.Parameter{},
+	Type:        function.StaticReturnType(cty.String),
+	Impl: func([]cty.Value, cty.Type) (cty.Value, error) {
+		return cty.StringVal(time.Now().UTC().Format(time.RFC3339)), nil
+	},
+})
+
+var uuidFunc = function.New(&function.Spec{
+	Description: "Generates a random UUID (version 4).",
+	Params:      []function.Parameter{},
+	Type:        function.StaticReturnType(cty.String),
+	Impl: func([]cty.Value, cty.Type) (cty.Value, error) {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return cty.StringVal(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), nil
+	},
+})
+
+var envFunc = function.New(&function.Spec{
+	Description: "Gets the value of an environment variable, or an empty string when it is not set.",
+	Params: []function.Parameter{
+		{Name: "name", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.StringVal(os.Getenv(args[0].AsString())), nil
+	},
+})
+
+func makeFileFunc(baseDir string) function.Function {
+	return function.New(&function.Spec{
+		Description: "Reads the contents of a file, which must contain UTF-8 text.",
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			text, err := readFile(baseDir, args[0].AsString())
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+			}
+			return cty.StringVal(text), nil
+		},
+	})
+}
+
+func makeTemplateFileFunc(baseDir string, funcs func() map[string]function.Function) function.Function {
+	return function.New(&function.Spec{
+		Description: "Renders the template in a file using the variables given in an object.",
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+			{Name: "vars", Type: cty.DynamicPseudoType},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			text, err := readFile(baseDir, path)
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+			}
+
+			vars := args[1]
+			if vars.IsNull() || !vars.Type().IsObjectType() && !vars.Type().IsMapType() {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "vars must be an object")
+			}
+
+			tmpl, diags := hclsyntax.ParseTemplate([]byte(text), path, hcl.InitialPos)
+			if diags.HasErrors() {
+				return cty.UnknownVal(cty.String), function.NewArgError(0, diags)
+			}
+
+			v, diags := tmpl.Value(&hcl.EvalContext{
+				Variables: vars.AsValueMap(),
+				Functions: funcs(),
+			})
+			if diags.HasErrors() {
+				return cty.UnknownVal(cty.String), diags
+			}
+			return convert.Convert(v, cty.String)
+		},
+	})
+}
+
+func readFile(baseDir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("contents of %s are not valid UTF-8", path)
+	}
+	return string(data), nil
+}
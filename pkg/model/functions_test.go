@@ -0,0 +1,75 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Functions", func() {
+
+	var (
+		baseDir string
+		eval    func(string) (cty.Value, error)
+	)
+
+	BeforeEach(func() {
+		baseDir = GinkgoT().TempDir()
+		eval = func(text string) (cty.Value, error) {
+			e, diags := hclsyntax.ParseExpression([]byte(text), "-", hcl.InitialPos)
+			Expect(diags).To(BeEmpty())
+			return model.ExpressionFromHCL(e).Value(&model.Scope{
+				Functions: model.Functions(baseDir),
+			})
+		}
+	})
+
+	DescribeTable("examples",
+		func(text string, expected cty.Value) {
+			v, err := eval(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v.Equals(expected).True()).To(BeTrue(), v.GoString())
+		},
+		Entry("upper", `upper("admin")`, cty.StringVal("ADMIN")),
+		Entry("format", `format("%s-%d", "row", 3)`, cty.StringVal("row-3")),
+		Entry("regex_replace", `regex_replace("a1b2", "[0-9]", "")`, cty.StringVal("ab")),
+		Entry("length", `length([1, 2, 3])`, cty.NumberIntVal(3)),
+		Entry("jsonencode", `jsonencode({ a = 1 })`, cty.StringVal(`{"a":1}`)),
+		Entry("base64encode", `base64encode("hello")`, cty.StringVal("aGVsbG8=")),
+		Entry("base64decode", `base64decode("aGVsbG8=")`, cty.StringVal("hello")),
+		Entry("urlencode", `urlencode("a b&c")`, cty.StringVal("a+b%26c")),
+		Entry("try", `try(tonumber("x"), 0)`, cty.NumberIntVal(0)),
+	)
+
+	It("generates a UUID", func() {
+		v, err := eval("uuid()")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.AsString()).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	})
+
+	It("reads environment variables", func() {
+		GinkgoT().Setenv("AUTOGUN_TEST_ENV", "value")
+		Expect(eval(`env("AUTOGUN_TEST_ENV")`)).To(Equal(cty.StringVal("value")))
+	})
+
+	It("reads files relative to the base directory", func() {
+		Expect(os.WriteFile(filepath.Join(baseDir, "user.txt"), []byte("admin"), 0644)).To(Succeed())
+		Expect(eval(`file("user.txt")`)).To(Equal(cty.StringVal("admin")))
+	})
+
+	It("renders template files with functions", func() {
+		Expect(os.WriteFile(filepath.Join(baseDir, "greeting.tpl"), []byte("Hello, ${upper(name)}!"), 0644)).To(Succeed())
+		Expect(eval(`templatefile("greeting.tpl", { name = "admin" })`)).To(Equal(cty.StringVal("Hello, ADMIN!")))
+	})
+})
@@ -0,0 +1,259 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lint checks automations for problems which are not errors but which
+// make them slow, unreliable or confusing. Each problem is reported as a
+// warning by a named rule.
+//
+// Rules are enabled or disabled using the lint block of the workspace:
+//
+//	lint {
+//	  rules = {
+//	    fixed-sleep = false
+//	  }
+//	}
+//
+// A warning is suppressed by a comment which names its rule, either at the
+// end of the line which is reported or on the line before it:
+//
+//	# autogun:ignore fixed-sleep
+//	sleep {
+//	  duration = "1s"
+//	}
+//
+// A comment which names no rules suppresses the warnings of every rule.
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Rule checks each automation of the workspace for a particular problem
+type Rule struct {
+	// Name identifies the rule in the lint block and in suppression comments
+	Name string
+
+	// Description explains the problem that the rule detects
+	Description string
+
+	// Check reports the problems found in the automation of the pass
+	Check func(*Pass)
+}
+
+// Pass provides the automation which is checked by a rule
+type Pass struct {
+	// Automation is the automation which is checked
+	Automation *config.Automation
+
+	// Model contains the automations of the workspace and its imports, which
+	// is used to resolve flows
+	Model *model.Model
+
+	rule   *Rule
+	called map[string]bool
+	diags  hcl.Diagnostics
+}
+
+// ruleExtra identifies the rule which reported a diagnostic
+type ruleExtra string
+
+// suppressionPrefix starts a comment which suppresses warnings
+const suppressionPrefix = "autogun:ignore"
+
+// Rules contains the rules which are checked by the linter
+var Rules = []*Rule{
+	fixedSleep,
+	taskAfterStop,
+	duplicateScreenshot,
+	preferQuery,
+	unusedCapture,
+	uncalledAutomationWithParams,
+}
+
+// Reportf reports a warning about the subject
+func (p *Pass) Reportf(subject hcl.Range, summary string, format string, args ...any) {
+	p.diags = append(p.diags, &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(format, args...) + fmt.Sprintf(" (%s)", p.rule.Name),
+		Subject:  subject.Ptr(),
+		Extra:    ruleExtra(p.rule.Name),
+	})
+}
+
+// Called determines whether a flow of the workspace runs the automation with
+// the given qualified name
+func (p *Pass) Called(qname string) bool {
+	return p.called[qname]
+}
+
+// Rule gets the name of the rule which reported the diagnostic
+func (r ruleExtra) Rule() string {
+	return string(r)
+}
+
+// RuleName gets the name of the rule which reported a diagnostic, which is
+// empty for diagnostics which were not reported by the linter
+func RuleName(d *hcl.Diagnostic) string {
+	r, _ := d.Extra.(ruleExtra)
+	return string(r)
+}
+
+// Lint checks the automations declared by files of the workspace using the
+// rules which are enabled by their lint blocks. Imported packages are used to
+// resolve flows but are not checked. The sources of the files are used to
+// find suppression comments.
+func Lint(sources map[string]*hcl.File, files ...*config.File) hcl.Diagnostics {
+	enabled, diags := enabledRules(files)
+	m := model.New(files...)
+	called := calledAutomations(m, files)
+
+	var found hcl.Diagnostics
+	for _, f := range files {
+		if f.Package() != "" {
+			continue
+		}
+		for _, a := range f.Automations {
+			for _, rule := range enabled {
+				p := &Pass{
+					Automation: a,
+					Model:      m,
+					rule:       rule,
+					called:     called,
+				}
+				rule.Check(p)
+				found = append(found, p.diags...)
+			}
+		}
+	}
+
+	return append(diags, suppress(sources, found)...)
+}
+
+// enabledRules determines the rules which are enabled by the lint blocks of
+// the workspace, reporting the names of rules which do not exist
+func enabledRules(files []*config.File) ([]*Rule, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	disabled := map[string]bool{}
+	for _, f := range files {
+		if f.Package() != "" || f.Lint == nil {
+			continue
+		}
+		for name, on := range f.Lint.Rules {
+			if lookupRule(name) == nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "Unknown lint rule",
+					Detail:   fmt.Sprintf("There is no lint rule named %q.", name),
+					Subject:  f.Lint.RulesRange.Ptr(),
+				})
+				continue
+			}
+			disabled[name] = !on
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(Rules), func(r *Rule) bool {
+		return disabled[r.Name]
+	}), diags
+}
+
+func lookupRule(name string) *Rule {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// calledAutomations gets the qualified names of the automations which are
+// run by flows
+func calledAutomations(m *model.Model, files []*config.File) map[string]bool {
+	res := map[string]bool{}
+	for _, f := range files {
+		for _, a := range f.Automations {
+			config.WalkTasks(a.Tasks, func(t config.Task) {
+				flow, ok := t.(*config.Flow)
+				if !ok {
+					return
+				}
+				if target, err := m.ResolveAutomation(flow.Name, f.Package()); err == nil {
+					res[target.QualifiedName()] = true
+				}
+			})
+		}
+	}
+	return res
+}
+
+// suppress removes the diagnostics which are suppressed by comments
+func suppress(sources map[string]*hcl.File, diags hcl.Diagnostics) hcl.Diagnostics {
+	byFile := map[string]map[int][]string{}
+	return slices.DeleteFunc(diags, func(d *hcl.Diagnostic) bool {
+		if d.Subject == nil {
+			return false
+		}
+		filename := d.Subject.Filename
+		lines, ok := byFile[filename]
+		if !ok {
+			lines = suppressions(sources[filename], filename)
+			byFile[filename] = lines
+		}
+		rules, ok := lines[d.Subject.Start.Line]
+		return ok && (len(rules) == 0 || slices.Contains(rules, RuleName(d)))
+	})
+}
+
+// suppressions finds the comments of the file which suppress warnings,
+// indexed by the line which they apply to. A comment applies to the line
+// where it appears unless it is on a line by itself, in which case it applies
+// to the next line.
+func suppressions(file *hcl.File, filename string) map[int][]string {
+	res := map[int][]string{}
+	if file == nil || strings.HasSuffix(filename, ".json") {
+		return res
+	}
+
+	tokens, _ := hclsyntax.LexConfig(file.Bytes, filename, hcl.InitialPos)
+	lastLine := 0
+	for _, tok := range tokens {
+		if tok.Type != hclsyntax.TokenComment {
+			if tok.Type != hclsyntax.TokenNewline {
+				lastLine = tok.Range.Start.Line
+			}
+			continue
+		}
+
+		text := strings.TrimSpace(string(tok.Bytes))
+		for _, prefix := range []string{"#", "//", "/*"} {
+			text = strings.TrimPrefix(text, prefix)
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+		rest, ok := strings.CutPrefix(text, suppressionPrefix)
+		if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+
+		line := tok.Range.Start.Line
+		if lastLine != line {
+			// Line comments include the newline which ends them
+			line = tok.Range.End.Line
+			if !strings.HasSuffix(string(tok.Bytes), "\n") {
+				line++
+			}
+		}
+		res[line] = append(res[line], strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	return res
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lint_test
+
+import (
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model/lint"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/onsi/gomega/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Lint", func() {
+
+	var lintSource = func(src string) hcl.Diagnostics {
+		p := config.NewParser(nil)
+		f, diags := p.ParseSource("a.autog", []byte(src))
+		Expect(diags).To(BeEmpty())
+		Expect(config.ResolveSelectors(f)).To(BeEmpty())
+		return lint.Lint(p.Files(), f)
+	}
+
+	var warning = func(rule string, summary string) types.GomegaMatcher {
+		return PointTo(MatchFields(IgnoreExtras, Fields{
+			"Severity": Equal(hcl.DiagWarning),
+			"Summary":  Equal(summary),
+			"Detail":   HaveSuffix("(" + rule + ")"),
+		}))
+	}
+
+	DescribeTable("rules",
+		func(src string, expected types.GomegaMatcher) {
+			Expect(lintSource(src)).To(expected)
+		},
+		Entry("fixed-sleep", `
+automation "main" {
+  sleep {
+    duration = "1s"
+  }
+}`, ConsistOf(warning("fixed-sleep", "Fixed sleep"))),
+
+		Entry("task-after-stop", `
+automation "main" {
+  stop {}
+  reload {}
+}`, ConsistOf(warning("task-after-stop", "Task after stop"))),
+
+		Entry("duplicate-screenshot", `
+automation "main" {
+  screenshot "page" {}
+  screenshot "page" {}
+}`, ConsistOf(warning("duplicate-screenshot", "Duplicate screenshot name"))),
+
+		Entry("duplicate-screenshot in for_each", `
+automation "main" {
+  for_each "rows" {
+    items = [1, 2]
+    screenshot "row" {}
+  }
+}`, ConsistOf(warning("duplicate-screenshot", "Duplicate screenshot name"))),
+
+		Entry("prefer-query", `
+automation "main" {
+  click {
+    selector {
+      target = "#submit"
+      by     = "SEARCH"
+    }
+  }
+  click {
+    selector {
+      target = "Sign in"
+      by     = "SEARCH"
+    }
+  }
+}`, ConsistOf(warning("prefer-query", "Selector could use QUERY"))),
+
+		Entry("unused-capture", `
+automation "main" {
+  title "page_title" {}
+  eval "count" {
+    script = "1"
+  }
+
+  output "count" {
+    value = count
+  }
+}`, ConsistOf(warning("unused-capture", "Unused captured value"))),
+
+		Entry("uncalled-automation-with-params", `
+automation "login" {
+  params = ["user"]
+}
+
+automation "checkout" {
+  params = ["item"]
+}
+
+automation "main" {
+  flow "login" {
+    args = { user = "a" }
+  }
+}`, ConsistOf(warning("uncalled-automation-with-params", "Uncalled automation with params"))),
+	)
+
+	It("uses the flows of imported packages and does not check them", func() {
+		p := config.NewParser(nil)
+		f, diags := p.ParseSource("a.autog", []byte(`
+import "shared" {
+  source = "shared"
+}
+
+automation "login" {
+  params = ["user"]
+}`))
+		Expect(diags).To(BeEmpty())
+
+		shared, diags := p.ParseSource("shared/b.autog", []byte(`
+automation "main" {
+  sleep {
+    duration = "1s"
+  }
+  flow "login" {
+    args = { user = "a" }
+  }
+}`))
+		Expect(diags).To(BeEmpty())
+		shared.SetPackage("shared")
+
+		Expect(lint.Lint(p.Files(), f, shared)).To(BeEmpty())
+	})
+
+	It("disables rules using the lint block", func() {
+		Expect(lintSource(`
+lint {
+  rules = {
+    fixed-sleep = false
+  }
+}
+
+automation "main" {
+  sleep {
+    duration = "1s"
+  }
+}`)).To(BeEmpty())
+	})
+
+	It("reports unknown rules", func() {
+		Expect(lintSource(`
+lint {
+  rules = {
+    no-such-rule = false
+  }
+}`)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Unknown lint rule"),
+		}))))
+	})
+
+	DescribeTable("suppression comments",
+		func(src string) {
+			Expect(lintSource(src)).To(BeEmpty())
+		},
+		Entry("on the previous line", `
+automation "main" {
+  # autogun:ignore fixed-sleep
+  sleep {
+    duration = "1s"
+  }
+}`),
+		Entry("at the end of the line", `
+automation "main" {
+  sleep { // autogun:ignore fixed-sleep
+    duration = "1s"
+  }
+}`),
+		Entry("without rules", `
+automation "main" {
+  /* autogun:ignore */
+  sleep {
+    duration = "1s"
+  }
+}`),
+	)
+
+	It("keeps warnings of rules which are not suppressed", func() {
+		Expect(lintSource(`
+automation "main" {
+  # autogun:ignore task-after-stop
+  sleep {
+    duration = "1s"
+  }
+  sleep { # autogun:ignore fixed-sleep
+    duration = "1s"
+  }
+  sleep {
+    duration = "1s"
+  }
+}`)).To(HaveLen(2))
+	})
+
+	It("identifies the rule of a warning", func() {
+		diags := lintSource(`
+automation "main" {
+  sleep {
+    duration = "1s"
+  }
+}`)
+		Expect(lint.RuleName(diags[0])).To(Equal("fixed-sleep"))
+	})
+})
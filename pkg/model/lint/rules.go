@@ -0,0 +1,216 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"cmp"
+	"regexp"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var fixedSleep = &Rule{
+	Name:        "fixed-sleep",
+	Description: "sleep waits for a fixed duration instead of for the page",
+	Check: func(p *Pass) {
+		config.WalkTasks(p.Automation.Tasks, func(t config.Task) {
+			if s, ok := t.(*config.Sleep); ok {
+				p.Reportf(s.DeclRange, "Fixed sleep",
+					"Waiting for a fixed duration is slow and unreliable. Use wait_visible or the options of a task to wait for an element instead.")
+			}
+		})
+	},
+}
+
+var taskAfterStop = &Rule{
+	Name:        "task-after-stop",
+	Description: "tasks follow a stop task, which stops loading the page",
+	Check: func(p *Pass) {
+		walkTaskLists(p.Automation.Tasks, func(tasks []config.Task) {
+			for i, t := range tasks {
+				if _, ok := t.(*config.Stop); ok && i+1 < len(tasks) {
+					p.Reportf(config.TaskRange(tasks[i+1]), "Task after stop",
+						"The stop task stops loading the page, so the tasks which follow it can act on a page which is incomplete.")
+					return
+				}
+			}
+		})
+	},
+}
+
+var duplicateScreenshot = &Rule{
+	Name:        "duplicate-screenshot",
+	Description: "screenshots have the same name, so one overwrites the other",
+	Check: func(p *Pass) {
+		seen := map[string]*config.Screenshot{}
+		var visit func(tasks []config.Task, loop bool)
+		visit = func(tasks []config.Task, loop bool) {
+			for _, t := range tasks {
+				switch t := t.(type) {
+				case *config.Screenshot:
+					name := cmp.Or(t.Name, "screenshot.png")
+					if prev, ok := seen[name]; ok {
+						p.Reportf(t.DeclRange, "Duplicate screenshot name",
+							"The screenshot %q overwrites the screenshot taken at %s.", name, prev.DeclRange)
+						continue
+					}
+					seen[name] = t
+					if loop {
+						p.Reportf(t.DeclRange, "Duplicate screenshot name",
+							"The screenshot %q is taken by each iteration of for_each, which overwrites the screenshot taken by the previous iteration.", name)
+					}
+				case *config.ForEach:
+					visit(t.Tasks, true)
+				default:
+					for _, tasks := range childLists(t) {
+						visit(tasks, loop)
+					}
+				}
+			}
+		}
+		visit(p.Automation.Tasks, false)
+	},
+}
+
+// cssSelector matches targets which are certainly CSS selectors, starting
+// with an ID or class and optionally combined with other simple selectors
+var cssSelector = regexp.MustCompile(`^[#.][A-Za-z_][\w-]*(?:\s*[>+~]?\s*[#.]?[A-Za-z_][\w-]*|\[[^\]]*\]|:[\w-]+)*$`)
+
+var preferQuery = &Rule{
+	Name:        "prefer-query",
+	Description: "a selector uses SEARCH for a target which QUERY can find",
+	Check: func(p *Pass) {
+		config.WalkTasks(p.Automation.Tasks, func(t config.Task) {
+			for _, s := range config.TaskSelectors(t) {
+				if s.Ref != "" || s.By != config.BySearch || s.Target == nil {
+					continue
+				}
+				target, diags := s.Target.Value(nil)
+				if diags.HasErrors() || target.Type() != cty.String || !target.IsKnown() || target.IsNull() {
+					continue
+				}
+				if cssSelector.MatchString(target.AsString()) {
+					p.Reportf(s.DeclRange, "Selector could use QUERY",
+						"The target %q is a CSS selector, which QUERY finds without searching the text and XPath of the document as SEARCH does.", target.AsString())
+				}
+			}
+		})
+	},
+}
+
+var unusedCapture = &Rule{
+	Name:        "unused-capture",
+	Description: "a captured value is dropped because the automation declares outputs",
+	Check: func(p *Pass) {
+		a := p.Automation
+		if len(a.Outputs) == 0 {
+			return
+		}
+
+		var (
+			used     = map[string]bool{}
+			captures []config.Task
+		)
+		addUsed := func(expr hcl.Expression) {
+			if expr == nil {
+				return
+			}
+			for _, t := range expr.Variables() {
+				used[t.RootName()] = true
+			}
+		}
+		config.WalkTasks(a.Tasks, func(t config.Task) {
+			for _, expr := range config.TaskExpressions(t) {
+				addUsed(expr)
+			}
+			switch t.(type) {
+			case *config.Title, *config.Eval, *config.InnerHTML, *config.OuterHTML,
+				*config.Text, *config.Attribute, *config.Value, *config.Count, *config.Extract:
+				captures = append(captures, t)
+			}
+		})
+		addUsed(a.Returns)
+		for _, o := range a.Outputs {
+			addUsed(o.Value)
+		}
+
+		for _, t := range captures {
+			name := captureName(t)
+			if name != "" && !used[name] {
+				p.Reportf(config.TaskRange(t), "Unused captured value",
+					"The value %q is captured but never used, and it is not written to the result because automation %q declares outputs.", name, a.Name)
+			}
+		}
+	},
+}
+
+// uncalledAutomationWithParams only reports automations which have params.
+// Other automations are entry points that can be run directly, so they are
+// reachable even when no flow runs them.
+var uncalledAutomationWithParams = &Rule{
+	Name:        "uncalled-automation-with-params",
+	Description: "an automation which requires params, so that only a flow can run it, is not run by any flow",
+	Check: func(p *Pass) {
+		a := p.Automation
+		if len(a.Params) == 0 || p.Called(a.Name) {
+			return
+		}
+		p.Reportf(a.NameRange, "Uncalled automation with params",
+			"The automation %q has params, so it can only be run by a flow, but no flow runs it.", a.Name)
+	},
+}
+
+func captureName(t config.Task) string {
+	switch t := t.(type) {
+	case *config.Title:
+		return t.Name
+	case *config.Eval:
+		return t.Name
+	case *config.InnerHTML:
+		return t.Name
+	case *config.OuterHTML:
+		return t.Name
+	case *config.Text:
+		return t.Name
+	case *config.Attribute:
+		return t.Name
+	case *config.Value:
+		return t.Name
+	case *config.Count:
+		return t.Name
+	case *config.Extract:
+		return t.Name
+	}
+	return ""
+}
+
+// walkTaskLists calls fn for the list of tasks and for each list of tasks
+// nested within them
+func walkTaskLists(tasks []config.Task, fn func([]config.Task)) {
+	fn(tasks)
+	for _, t := range tasks {
+		for _, child := range childLists(t) {
+			walkTaskLists(child, fn)
+		}
+	}
+}
+
+func childLists(t config.Task) [][]config.Task {
+	switch t := t.(type) {
+	case *config.If:
+		return [][]config.Task{t.Tasks, t.Else}
+	case *config.ForEach:
+		return [][]config.Task{t.Tasks}
+	case *config.Try:
+		return [][]config.Task{t.Tasks, t.Catch, t.Finally}
+	case *config.Retry:
+		return [][]config.Task{t.Tasks}
+	case *config.Timeout:
+		return [][]config.Task{{t.Task}}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Local is a named value computed from an expression, referenced in
+// expressions as local.NAME
+type Local struct {
+	Name  string
+	Value Expression
+}
+
+// SortLocals orders the locals so that each local appears after the locals
+// that it references. A cycle between locals is reported as an error
+// which contains diagnostics.
+func SortLocals(locals []*Local) ([]*Local, error) {
+	byName := make(map[string]*Local, len(locals))
+	for _, l := range locals {
+		byName[l.Name] = l
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	var (
+		res   = make([]*Local, 0, len(locals))
+		state = map[string]int{}
+		path  []string
+		diags hcl.Diagnostics
+		visit func(*Local) bool
+	)
+
+	visit = func(l *Local) bool {
+		switch state[l.Name] {
+		case visited:
+			return true
+		case visiting:
+			cycle := slices.Concat(path[slices.Index(path, l.Name):], []string{l.Name})
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Cycle in local values",
+				Detail: fmt.Sprintf(
+					"The local values refer to each other in a cycle: %s.",
+					"local."+strings.Join(cycle, " -> local."),
+				),
+				Subject: expressionRange(l.Value),
+			})
+			return false
+		}
+
+		state[l.Name] = visiting
+		path = append(path, l.Name)
+		for _, ref := range localReferences(l.Value) {
+			dep, ok := byName[ref]
+			if ok && !visit(dep) {
+				return false
+			}
+		}
+		path = path[:len(path)-1]
+		state[l.Name] = visited
+		res = append(res, l)
+		return true
+	}
+
+	for _, l := range locals {
+		if !visit(l) {
+			return nil, diags
+		}
+	}
+	return res, nil
+}
+
+func localReferences(e Expression) []string {
+	if e == nil {
+		return nil
+	}
+	var res []string
+	for _, v := range e.Variables() {
+		if name, ok := strings.CutPrefix(v, "local."); ok {
+			res = append(res, name)
+		}
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("SortLocals", func() {
+
+	var local = func(name, expr string) *model.Local {
+		e, diags := hclsyntax.ParseExpression([]byte(expr), name+".autog", hcl.InitialPos)
+		Expect(diags).To(BeEmpty())
+		return &model.Local{Name: name, Value: model.ExpressionFromHCL(e)}
+	}
+
+	var names = func(locals []*model.Local) []string {
+		res := make([]string, len(locals))
+		for i, l := range locals {
+			res[i] = l.Name
+		}
+		return res
+	}
+
+	It("orders locals after their dependencies", func() {
+		sorted, err := model.SortLocals([]*model.Local{
+			local("c", "local.b"),
+			local("b", `"${local.a}/b"`),
+			local("a", "var.base"),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(sorted)).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("reports a cycle with its range", func() {
+		_, err := model.SortLocals([]*model.Local{
+			local("a", "local.b"),
+			local("b", "local.a"),
+		})
+
+		Expect(err).To(BeAssignableToTypeOf(hcl.Diagnostics{}))
+		Expect(err.(hcl.Diagnostics)).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Cycle in local values"),
+			"Detail":  ContainSubstring("local.a -> local.b -> local.a"),
+			"Subject": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Filename": Equal("a.autog"),
+			})),
+		}))))
+	})
+})
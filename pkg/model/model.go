@@ -5,30 +5,122 @@
 package model
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/Carbonfrost/autogun/pkg/config"
 )
 
 // Model is the collection of automations available in the workspace.
 type Model struct {
 	Automations []*Automation
+	Variables   []*Variable
+	Locals      []*Local
+
+	// Browsers contains the browser profiles declared by the workspace
+	Browsers []*Browser
+
+	// Devices contains the custom devices declared by the workspace. See
+	// [Model.LookupDevice] to include the built-in devices.
+	Devices []Device
+
+	// Packages contains the names of the imported packages
+	Packages []string
 }
 
-// New creates a model from the given configuration files
+// New creates a model from the given configuration files, whose references
+// to named selectors must already be resolved by [config.ResolveSelectors].
+// The files are not modified.
 func New(files ...*config.File) *Model {
 	m := &Model{}
 	for _, file := range files {
+		m.addPackage(file.Package())
 		for _, auto := range fromConfigFile(file) {
 			m.Automations = append(m.Automations, auto)
+			m.addPackage(auto.Package)
 		}
+		m.Variables = append(m.Variables, variablesFromConfigFile(file)...)
+		m.Locals = append(m.Locals, localsFromConfigFile(file)...)
+		m.Browsers = append(m.Browsers, browsersFromConfigFile(file)...)
+		m.Devices = append(m.Devices, devicesFromConfigFile(file)...)
 	}
 	return m
 }
 
-// Automation retrieves the automation by name
+func (m *Model) addPackage(pkg string) {
+	if pkg != "" && !slices.Contains(m.Packages, pkg) {
+		m.Packages = append(m.Packages, pkg)
+	}
+}
+
+// Automation retrieves the automation by name as the workspace refers to it,
+// which returns nil when the name cannot be resolved. See
+// [Model.ResolveAutomation].
 func (m *Model) Automation(name string) *Automation {
+	auto, _ := m.ResolveAutomation(name, "")
+	return auto
+}
+
+// ResolveAutomation retrieves the automation by name as it is referred to from
+// the package from, which is empty for the workspace. A name in the form
+// @PACKAGE/NAME refers to an automation in an imported package. Other names
+// refer to an automation of the package from, then of the workspace, or
+// otherwise to the only automation with that name among the imported
+// packages.
+func (m *Model) ResolveAutomation(name string, from string) (*Automation, error) {
+	pkg, short := splitQName(name)
+	if pkg != "" {
+		if !slices.Contains(m.Packages, pkg) {
+			return nil, fmt.Errorf("automation not found %q: package %q is not imported", name, pkg)
+		}
+		for _, auto := range m.Automations {
+			if auto.Package == pkg && auto.Name == short {
+				return auto, nil
+			}
+		}
+		return nil, fmt.Errorf("automation not found %q", name)
+	}
+
+	if from != "" {
+		for _, auto := range m.Automations {
+			if auto.Package == from && auto.Name == name {
+				return auto, nil
+			}
+		}
+	}
+
+	var candidates []*Automation
 	for _, auto := range m.Automations {
-		if auto.Name == name {
-			return auto
+		if auto.Name != name {
+			continue
+		}
+		if auto.Package == "" {
+			return auto, nil
+		}
+		candidates = append(candidates, auto)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("automation not found %q", name)
+	case 1:
+		return candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i, auto := range candidates {
+			names[i] = auto.QualifiedName()
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("automation %q is ambiguous; use one of %s", name, strings.Join(names, ", "))
+	}
+}
+
+// Variable retrieves the variable by name
+func (m *Model) Variable(name string) *Variable {
+	for _, v := range m.Variables {
+		if v.Name == name {
+			return v
 		}
 	}
 	return nil
@@ -0,0 +1,210 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Model", func() {
+
+	Describe("ResolveAutomation", func() {
+
+		var packageFile = func(pkg string, names ...string) *config.File {
+			f := new(config.File)
+			f.SetPackage(pkg)
+			for _, name := range names {
+				f.Automations = append(f.Automations, &config.Automation{Name: name})
+			}
+			return f
+		}
+
+		var m *model.Model
+
+		BeforeEach(func() {
+			m = model.New(
+				packageFile("", "main", "login"),
+				packageFile("shared", "login", "logout"),
+				packageFile("util", "logout", "helper"),
+			)
+		})
+
+		DescribeTable("examples",
+			func(name string, expected string) {
+				auto, err := m.ResolveAutomation(name, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(auto.QualifiedName()).To(Equal(expected))
+			},
+			Entry("workspace automation", "main", "main"),
+			Entry("workspace automation preferred over packages", "login", "login"),
+			Entry("package-scoped name", "@shared/login", "@shared/login"),
+			Entry("unique name among packages", "helper", "@util/helper"),
+		)
+
+		DescribeTable("examples from a package",
+			func(from string, name string, expected string) {
+				auto, err := m.ResolveAutomation(name, from)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(auto.QualifiedName()).To(Equal(expected))
+			},
+			Entry("automation of the package preferred over the workspace", "shared", "login", "@shared/login"),
+			Entry("name defined in several packages", "util", "logout", "@util/logout"),
+			Entry("workspace automation", "shared", "main", "main"),
+			Entry("unique name among other packages", "shared", "helper", "@util/helper"),
+			Entry("package-scoped name", "util", "@shared/logout", "@shared/logout"),
+		)
+
+		DescribeTable("errors",
+			func(name string, expected string) {
+				_, err := m.ResolveAutomation(name, "")
+				Expect(err).To(MatchError(expected))
+			},
+			Entry("missing", "missing", `automation not found "missing"`),
+			Entry("missing in package", "@shared/missing", `automation not found "@shared/missing"`),
+			Entry("package not imported", "@other/login", `automation not found "@other/login": package "other" is not imported`),
+			Entry("ambiguous", "logout", `automation "logout" is ambiguous; use one of @shared/logout, @util/logout`),
+		)
+	})
+
+	Describe("New", func() {
+
+		var selectorFile = func() *config.File {
+			return &config.File{
+				Selectors: []*config.Selector{
+					{Name: "login", Target: hcl.StaticExpr(cty.StringVal("#login"), hcl.Range{}), By: config.ByQuery},
+				},
+				Automations: []*config.Automation{
+					{
+						Tasks: []config.Task{
+							&config.Click{
+								Selectors: []*config.Selector{{Ref: "selector.login"}},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("converts resolved references to named selectors", func() {
+			file := selectorFile()
+			Expect(config.ResolveSelectors(file)).To(BeEmpty())
+
+			m := model.New(file)
+			sel := m.Automations[0].Tasks[0].(*model.Click).Selectors[0]
+			Expect(sel.By).To(Equal(model.ByQuery))
+			Expect(sel.Target.Value(nil)).To(Equal(cty.StringVal("#login")))
+		})
+
+		It("does not resolve the files", func() {
+			file := selectorFile()
+			model.New(file)
+
+			ref := file.Automations[0].Tasks[0].(*config.Click).Selectors[0]
+			Expect(ref.Target).To(BeNil())
+		})
+	})
+
+	Describe("ResolveBrowser", func() {
+
+		var m *model.Model
+
+		BeforeEach(func() {
+			shared := &config.File{
+				Browsers: []*config.Browser{{Name: "ci"}},
+			}
+			shared.SetPackage("shared")
+
+			m = model.New(
+				&config.File{
+					Browsers: []*config.Browser{
+						{Name: "default", Env: map[string]string{"TZ": "UTC", "LANG": "C"}, WindowSize: []int{800, 600}},
+						{Name: "local"},
+					},
+				},
+				shared,
+			)
+		})
+
+		It("uses the default profile when no name is given", func() {
+			b, err := m.ResolveBrowser("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b.Name).To(Equal("default"))
+			Expect(b.Env).To(Equal([]string{"LANG=C", "TZ=UTC"}))
+			Expect(b.WindowSize).To(Equal(&model.WindowSize{Width: 800, Height: 600}))
+		})
+
+		It("returns an error when the profile is not declared by the workspace", func() {
+			_, err := m.ResolveBrowser("ci")
+			Expect(err).To(MatchError(`browser profile not found "ci"`))
+		})
+	})
+
+	Describe("LookupDevice", func() {
+
+		var m *model.Model
+
+		BeforeEach(func() {
+			m = model.New(&config.File{
+				Devices: []*config.Device{
+					{Name: "pixel9", Description: "Pixel 9", Width: 412, Height: 915, Scale: 2.625, Mobile: true},
+				},
+			})
+		})
+
+		It("finds devices declared by the workspace", func() {
+			d, ok := m.LookupDevice("pixel9")
+			Expect(ok).To(BeTrue())
+			Expect(d).To(MatchFields(IgnoreExtras, Fields{
+				"ID":     Equal("pixel9"),
+				"Name":   Equal("Pixel 9"),
+				"Width":  Equal(int64(412)),
+				"Mobile": BeTrue(),
+			}))
+		})
+
+		It("finds built-in devices", func() {
+			_, ok := m.LookupDevice("Pixel2")
+			Expect(ok).To(BeTrue())
+		})
+
+		It("lists custom devices with the built-in devices", func() {
+			Expect(m.AllDevices()).To(HaveLen(len(model.Devices()) + 1))
+			Expect(m.AllDevices()).To(ContainElement(HaveField("ID", "pixel9")))
+		})
+	})
+
+	Describe("ParseDevice", func() {
+
+		It("parses the inline form", func() {
+			d, err := model.ParseDevice("width=412,height=915,scale=2.5,mobile,touch=false")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(MatchFields(IgnoreExtras, Fields{
+				"ID":     Equal(model.InlineDeviceID),
+				"Width":  Equal(int64(412)),
+				"Height": Equal(int64(915)),
+				"Scale":  Equal(2.5),
+				"Mobile": BeTrue(),
+				"Touch":  BeFalse(),
+			}))
+		})
+
+		DescribeTable("errors",
+			func(spec string, expected string) {
+				_, err := model.ParseDevice(spec)
+				Expect(err).To(MatchError(ContainSubstring(expected)))
+			},
+			Entry("unknown setting", "width=1,height=1,color=red", `unknown setting "color"`),
+			Entry("invalid number", "width=wide,height=1", "width"),
+			Entry("missing height", "width=412", "must be positive"),
+		)
+	})
+})
@@ -5,7 +5,7 @@
 package model
 
 type Selector struct {
-	Target string
+	Target Expression
 	By     SelectorBy
 	On     SelectorOn
 }
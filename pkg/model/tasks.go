@@ -4,7 +4,12 @@
 
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
 
 // Task is the basis of a step within an automation. Each task type mirrors the
 // corresponding type in the config package, but without the HCL-specific
@@ -29,7 +34,7 @@ type Title struct {
 
 type Eval struct {
 	Name   string
-	Script string
+	Script Expression
 }
 
 type InnerHTML struct {
@@ -38,6 +43,61 @@ type InnerHTML struct {
 	Options   *Options
 }
 
+type OuterHTML struct {
+	Name      string
+	Selectors []*Selector
+	Options   *Options
+}
+
+type Text struct {
+	Name      string
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Attribute captures the value of the named attribute of the element
+type Attribute struct {
+	Name      string
+	Attribute string
+	Selectors []*Selector
+	Options   *Options
+}
+
+type Value struct {
+	Name      string
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Count captures the number of elements which match the selectors. No
+// element needs to match unless the options specify at_least.
+type Count struct {
+	Name      string
+	Selectors []*Selector
+	Options   *Options
+}
+
+// Extract captures a list of objects, one for each element matched by the
+// selectors. When Next is set, the element it matches is clicked to load each
+// following page, up to MaxPages.
+type Extract struct {
+	Name      string
+	Selectors []*Selector
+	Next      []*Selector
+	MaxPages  int
+	Fields    []*ExtractField
+	Options   *Options
+}
+
+// ExtractField is an attribute of the objects captured by Extract, which is
+// read from the first element within the root that matches its selectors
+type ExtractField struct {
+	Name      string
+	Selectors []*Selector
+	Attribute string
+	Type      cty.Type
+}
+
 type Blur struct {
 	Selectors []*Selector
 	Options   *Options
@@ -76,13 +136,39 @@ type Screenshot struct {
 	Options   *Options
 }
 
+// Assert verifies the state of the page. Unlike other tasks, the declaration
+// range is retained so that a failed assertion can be reported as a
+// diagnostic of the configuration.
+type Assert struct {
+	Condition    Expression
+	Title        Expression
+	TitleMatches Expression
+	TextContains Expression
+	Count        Expression
+	Attribute    string
+	Value        Expression
+	Message      Expression
+	Selectors    []*Selector
+	Options      *Options
+	DeclRange    hcl.Range
+}
+
+// Options controls how elements are matched by selectors. Durations are
+// expressed as strings such as "5s".
 type Options struct {
-	RetryInterval *time.Duration `mapstructure:"retry_interval"`
-	AtLeast       *int           `mapstructure:"at_least"`
+	RetryInterval Expression
+	AtLeast       Expression
+	Timeout       Expression
 }
 
+// DefaultQueryTimeout is the time to wait for elements to be matched when the
+// options do not specify a timeout
+const DefaultQueryTimeout = 30 * time.Second
+
+// Sleep pauses the automation. The duration is expressed as a string such
+// as "5s".
 type Sleep struct {
-	Duration time.Duration
+	Duration Expression
 }
 
 type Reload struct{}
@@ -91,30 +177,92 @@ type Stop struct{}
 
 type Version struct{}
 
+// If runs its tasks when the condition is true, otherwise the tasks in Else
+type If struct {
+	Condition Expression
+	Tasks     []Task
+	Else      []Task
+}
+
+// ForEach runs its tasks once for each item of Items, or when Items is nil,
+// once for each element matched by the selectors. Within the tasks, each.key
+// and each.value provide the current item.
+type ForEach struct {
+	Name      string
+	Items     Expression
+	Selectors []*Selector
+	Options   *Options
+	Tasks     []Task
+}
+
+// Try runs its tasks, and when they fail, runs the tasks in Catch with the error
+// available as error.message. The tasks in Finally always run.
+type Try struct {
+	Tasks   []Task
+	Catch   []Task
+	Finally []Task
+}
+
+// Retry runs its tasks again when they fail, up to Attempts times in total,
+// waiting Backoff before the first retry and scaling the delay by Multiplier
+// after each subsequent one. Location identifies the block in the attempts
+// reported by the result.
+type Retry struct {
+	Attempts   int
+	Backoff    time.Duration
+	Multiplier float64
+	Tasks      []Task
+	Location   string
+}
+
+// Timeout limits the time that its task can take. BlockType and Location
+// identify the task in the error reported when the time elapses.
+type Timeout struct {
+	Duration  time.Duration
+	Task      Task
+	BlockType string
+	Location  string
+}
+
+// Flow runs the automation with the given name, which receives the values of
+// Args as its params
 type Flow struct {
 	Name string
+	Args Expression
 }
 
 type Source struct {
 	Filename string
 }
 
+func (*Assert) taskSigil()          {}
+func (*Attribute) taskSigil()       {}
 func (*Blur) taskSigil()            {}
 func (*Clear) taskSigil()           {}
 func (*Click) taskSigil()           {}
+func (*Count) taskSigil()           {}
 func (*DoubleClick) taskSigil()     {}
 func (*Eval) taskSigil()            {}
+func (*Extract) taskSigil()         {}
 func (*Flow) taskSigil()            {}
+func (*ForEach) taskSigil()         {}
+func (*If) taskSigil()              {}
 func (*InnerHTML) taskSigil()       {}
 func (*Navigate) taskSigil()        {}
 func (*NavigateBack) taskSigil()    {}
 func (*NavigateForward) taskSigil() {}
+func (*OuterHTML) taskSigil()       {}
 func (*Reload) taskSigil()          {}
+func (*Retry) taskSigil()           {}
 func (*Screenshot) taskSigil()      {}
 func (*SendKeys) taskSigil()        {}
 func (*Sleep) taskSigil()           {}
 func (*Source) taskSigil()          {}
 func (*Stop) taskSigil()            {}
+func (*Text) taskSigil()            {}
+func (*Timeout) taskSigil()         {}
 func (*Title) taskSigil()           {}
+func (*Try) taskSigil()             {}
+func (*Value) taskSigil()           {}
 func (*Version) taskSigil()         {}
 func (*WaitVisible) taskSigil()     {}
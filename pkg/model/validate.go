@@ -0,0 +1,318 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// scopeNames are the names which are in scope for the expressions of every
+// automation, in addition to the values that the automation captures
+var scopeNames = map[string]bool{
+	"var":   true,
+	"local": true,
+	"param": true,
+	"each":  true,
+	"error": true,
+}
+
+// Validate checks the semantics of the files, which are decoded and whose
+// selectors have been resolved. Flows which cannot be resolved, references to
+// variables and locals which are not declared, references to values which are
+// never captured, cycles between locals, and automations, variables and
+// locals declared more than once are reported.
+func Validate(files ...*config.File) hcl.Diagnostics {
+	var (
+		diags      hcl.Diagnostics
+		m          = New(files...)
+		vars       = map[string]bool{}
+		varDecls   = map[string]*config.Variable{}
+		locals     = map[string]bool{}
+		localDecls = map[string]*config.Local{}
+		autos      = map[string]*config.Automation{}
+	)
+	for _, f := range files {
+		for _, v := range f.Variables {
+			vars[v.Name] = true
+
+			// Variables declared twice in the same file are reported when
+			// the file is decoded
+			prev, ok := varDecls[v.Name]
+			if !ok {
+				varDecls[v.Name] = v
+				continue
+			}
+			if prev.DeclRange.Filename != v.DeclRange.Filename {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate variable",
+					Detail:   fmt.Sprintf("The variable %q was already declared at %s.", v.Name, prev.DeclRange),
+					Subject:  &v.NameRange,
+				})
+			}
+		}
+		for _, l := range f.Locals {
+			locals[l.Name] = true
+
+			if prev, ok := localDecls[l.Name]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate local",
+					Detail:   fmt.Sprintf("The local %q was already declared at %s.", l.Name, prev.DeclRange),
+					Subject:  &l.NameRange,
+				})
+				continue
+			}
+			localDecls[l.Name] = l
+		}
+	}
+
+	// Cycles are otherwise found only when the locals are evaluated
+	if _, err := SortLocals(m.Locals); err != nil {
+		if cycle, ok := err.(hcl.Diagnostics); ok {
+			diags = append(diags, cycle...)
+		}
+	}
+
+	for _, f := range files {
+		for _, a := range f.Automations {
+			qname := a.Name
+			if f.Package() != "" {
+				qname = "@" + f.Package() + "/" + a.Name
+			}
+			if prev, ok := autos[qname]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate automation",
+					Detail:   fmt.Sprintf("The automation %q was already declared at %s.", a.Name, prev.DeclRange),
+					Subject:  &a.NameRange,
+				})
+			} else {
+				autos[qname] = a
+			}
+
+			diags = append(diags, validateAutomation(m, f.Package(), a, vars, locals)...)
+		}
+
+		for _, l := range f.Locals {
+			diags = append(diags, validateGlobals(l.Value, vars, locals)...)
+		}
+	}
+	return diags
+}
+
+// validateAutomation checks an automation of the package pkg, which is empty
+// for the workspace
+func validateAutomation(m *Model, pkg string, a *config.Automation, vars, locals map[string]bool) hcl.Diagnostics {
+	v := &automationValidator{
+		model:  m,
+		pkg:    pkg,
+		auto:   a,
+		vars:   vars,
+		locals: locals,
+	}
+
+	captured := map[string]bool{}
+	for _, p := range a.Params {
+		captured[p] = true
+	}
+
+	var exprs []hcl.Expression
+	if a.Returns != nil {
+		exprs = append(exprs, a.Returns)
+	}
+	for _, o := range a.Outputs {
+		exprs = append(exprs, o.Value)
+	}
+
+	v.validateScope(a.Tasks, captured, false, exprs)
+	return v.diags
+}
+
+// automationValidator checks the references of the tasks of an automation
+type automationValidator struct {
+	model        *Model
+	pkg          string
+	auto         *config.Automation
+	vars, locals map[string]bool
+	diags        hcl.Diagnostics
+}
+
+// validateScope checks the tasks which run in the same scope, which contains
+// the values captured by the enclosing scope. The bodies of for_each blocks
+// are checked in a scope of their own because the values which they capture
+// are not available after the loop. Additional expressions which are
+// evaluated in the scope can be specified. When the values returned by a flow
+// are not known until it runs, the scope is dynamic and references to
+// captured values cannot be checked.
+func (v *automationValidator) validateScope(tasks []config.Task, enclosing map[string]bool, dynamic bool, exprs []hcl.Expression) {
+	var (
+		captured = maps.Clone(enclosing)
+		loops    []*config.ForEach
+	)
+
+	walkScope(tasks, func(t config.Task) {
+		exprs = append(exprs, config.TaskExpressions(t)...)
+
+		switch t := t.(type) {
+		case *config.Title:
+			captured[t.Name] = true
+		case *config.Eval:
+			captured[t.Name] = true
+		case *config.InnerHTML:
+			captured[t.Name] = true
+		case *config.OuterHTML:
+			captured[t.Name] = true
+		case *config.Text:
+			captured[t.Name] = true
+		case *config.Attribute:
+			captured[t.Name] = true
+		case *config.Value:
+			captured[t.Name] = true
+		case *config.Count:
+			captured[t.Name] = true
+		case *config.Extract:
+			captured[t.Name] = true
+		case *config.ForEach:
+			captured[t.Name] = true
+			loops = append(loops, t)
+		case *config.Flow:
+			target, err := v.model.ResolveAutomation(t.Name, v.pkg)
+			if err != nil {
+				v.diags = append(v.diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unresolved flow",
+					Detail:   fmt.Sprintf("The flow %q cannot be run: %s.", t.Name, err),
+					Subject:  &t.NameRange,
+				})
+				return
+			}
+			names, ok := returnNames(target.Returns)
+			dynamic = dynamic || !ok
+			for _, name := range names {
+				captured[name] = true
+			}
+		}
+	})
+
+	for _, expr := range exprs {
+		v.diags = append(v.diags, validateGlobals(expr, v.vars, v.locals)...)
+		if dynamic {
+			continue
+		}
+		for _, t := range expr.Variables() {
+			name := t.RootName()
+			if scopeNames[name] || captured[name] {
+				continue
+			}
+			v.diags = append(v.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reference to uncaptured value",
+				Detail:   fmt.Sprintf("No task of automation %q captures a value named %q.", v.auto.Name, name),
+				Subject:  t.SourceRange().Ptr(),
+			})
+		}
+	}
+
+	for _, loop := range loops {
+		v.validateScope(loop.Tasks, captured, dynamic, nil)
+	}
+}
+
+// walkScope calls fn for each of the tasks and for each task nested within
+// them which runs in the same scope. Unlike [config.WalkTasks], the tasks of
+// for_each blocks are not visited.
+func walkScope(tasks []config.Task, fn func(config.Task)) {
+	for _, t := range tasks {
+		fn(t)
+
+		switch t := t.(type) {
+		case *config.If:
+			walkScope(t.Tasks, fn)
+			walkScope(t.Else, fn)
+		case *config.Try:
+			walkScope(t.Tasks, fn)
+			walkScope(t.Catch, fn)
+			walkScope(t.Finally, fn)
+		case *config.Retry:
+			walkScope(t.Tasks, fn)
+		case *config.Timeout:
+			walkScope([]config.Task{t.Task}, fn)
+		}
+	}
+}
+
+// validateGlobals reports references to variables and locals which have not
+// been declared
+func validateGlobals(expr hcl.Expression, vars, locals map[string]bool) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, t := range expr.Variables() {
+		if len(t) < 2 {
+			continue
+		}
+		attr, ok := t[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+
+		switch t.RootName() {
+		case "var":
+			if !vars[attr.Name] {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared variable",
+					Detail:   fmt.Sprintf("No variable named %q has been declared.", attr.Name),
+					Subject:  t.SourceRange().Ptr(),
+				})
+			}
+		case "local":
+			if !locals[attr.Name] {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared local",
+					Detail:   fmt.Sprintf("No local named %q has been declared.", attr.Name),
+					Subject:  t.SourceRange().Ptr(),
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// returnNames gets the names of the values returned by an automation, which
+// are known only when its returns are given by an object constructor
+func returnNames(returns Expression) ([]string, bool) {
+	if returns == nil {
+		return nil, true
+	}
+	e, ok := returns.(hclExpression)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := e.expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, false
+	}
+
+	names := make([]string, 0, len(obj.Items))
+	for _, item := range obj.Items {
+		if name := hcl.ExprAsKeyword(item.KeyExpr); name != "" {
+			names = append(names, name)
+			continue
+		}
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || !key.IsKnown() || key.Type() != cty.String {
+			return nil, false
+		}
+		names = append(names, key.AsString())
+	}
+	return names, true
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/onsi/gomega/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Validate", func() {
+
+	var validate = func(sources ...string) hcl.Diagnostics {
+		p := config.NewParser(nil)
+		files := make([]*config.File, 0, len(sources))
+		for i, src := range sources {
+			f, diags := p.ParseSource(string(rune('a'+i))+".autog", []byte(src))
+			Expect(diags).To(BeEmpty())
+			files = append(files, f)
+		}
+		return model.Validate(files...)
+	}
+
+	var diagnostic = func(summary string, detail string) types.GomegaMatcher {
+		return ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal(summary),
+			"Detail":  ContainSubstring(detail),
+		})))
+	}
+
+	It("accepts values captured by tasks and returned by flows", func() {
+		Expect(validate(`
+variable "base_url" {}
+
+automation "main" {
+  flow "login" {}
+
+  navigate {
+    url = "${var.base_url}/welcome?greeting=${greeting}"
+  }
+
+  title "page_title" {}
+
+  for_each "rows" {
+    items = [1, 2]
+    eval "row" {
+      script = "${each.value} + ${length(page_title)}"
+    }
+  }
+
+  output "rows" {
+    value = rows
+  }
+}
+
+automation "login" {
+  returns = { greeting = title }
+  title "title" {}
+}
+`)).To(BeEmpty())
+	})
+
+	It("accepts values captured from elements", func() {
+		Expect(validate(`
+automation "main" {
+  text "heading" {
+    selector = "h1"
+  }
+  attribute "link" {
+    selector  = "a"
+    attribute = "href"
+  }
+  value "email" {
+    selector = "#email"
+  }
+  outer_html "footer" {
+    selector = "footer"
+  }
+  count "rows" {
+    selector = "tr"
+  }
+
+  output "summary" {
+    value = "${heading} ${link} ${email} ${footer} ${rows}"
+  }
+}
+`)).To(BeEmpty())
+	})
+
+	It("accepts values captured by extract", func() {
+		Expect(validate(`
+automation "main" {
+  extract "products" {
+    root = ".product"
+    field "name" {
+      selector = "h2"
+    }
+  }
+
+  output "names" {
+    value = [for p in products : p.name]
+  }
+}
+`)).To(BeEmpty())
+	})
+
+	DescribeTable("errors",
+		func(src string, expected types.GomegaMatcher) {
+			Expect(validate(src)).To(expected)
+		},
+		Entry("unresolved flow", `
+automation "main" {
+  flow "missing" {}
+}
+`, diagnostic("Unresolved flow", `automation not found "missing"`)),
+
+		Entry("uncaptured value", `
+automation "main" {
+  navigate {
+    url = "https://example.com/${greeting}"
+  }
+}
+`, diagnostic("Reference to uncaptured value", `captures a value named "greeting"`)),
+
+		Entry("value captured within for_each", `
+automation "main" {
+  for_each "" {
+    items = [1, 2]
+    title "page_title" {}
+  }
+
+  output "title" {
+    value = page_title
+  }
+}
+`, diagnostic("Reference to uncaptured value", `captures a value named "page_title"`)),
+
+		Entry("undeclared variable", `
+automation "main" {
+  navigate {
+    url = var.base_url
+  }
+}
+`, diagnostic("Reference to undeclared variable", `No variable named "base_url"`)),
+
+		Entry("undeclared local", `
+locals {
+  url = local.base_url
+}
+`, diagnostic("Reference to undeclared local", `No local named "base_url"`)),
+	)
+
+	It("reports automations declared in more than one file", func() {
+		Expect(validate(`automation "main" {}`, `automation "main" {}`)).To(
+			diagnostic("Duplicate automation", `The automation "main" was already declared at a.autog`),
+		)
+	})
+
+	It("reports variables declared in more than one file", func() {
+		Expect(validate(`variable "base_url" {}`, `variable "base_url" {}`)).To(
+			diagnostic("Duplicate variable", `The variable "base_url" was already declared at a.autog`),
+		)
+	})
+
+	It("reports locals declared in more than one block", func() {
+		Expect(validate(`
+locals {
+  base_url = "https://example.com"
+}
+
+locals {
+  base_url = "https://example.org"
+}
+`)).To(diagnostic("Duplicate local", `The local "base_url" was already declared at a.autog:3`))
+	})
+
+	It("reports locals declared in more than one file", func() {
+		Expect(validate(`locals { a = 1 }`, `locals { a = 2 }`)).To(
+			diagnostic("Duplicate local", `The local "a" was already declared at a.autog`),
+		)
+	})
+
+	It("reports cycles between locals with their range", func() {
+		Expect(validate(`
+locals {
+  a = local.b
+  b = local.a
+}
+`)).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Summary": Equal("Cycle in local values"),
+			"Subject": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Filename": Equal("a.autog"),
+			})),
+		}))))
+	})
+
+	Describe("flows in imported packages", func() {
+
+		var validatePackages = func(sources map[string]string) hcl.Diagnostics {
+			p := config.NewParser(nil)
+			var files []*config.File
+			for _, pkg := range []string{"", "shared", "util"} {
+				src, ok := sources[pkg]
+				if !ok {
+					continue
+				}
+				f, diags := p.ParseSource(pkg+".autog", []byte(src))
+				Expect(diags).To(BeEmpty())
+				f.SetPackage(pkg)
+				files = append(files, f)
+			}
+			return model.Validate(files...)
+		}
+
+		It("resolves names within the package of the flow", func() {
+			Expect(validatePackages(map[string]string{
+				"": `automation "main" {}`,
+				"shared": `
+automation "run" {
+  flow "login" {}
+  navigate {
+    url = token
+  }
+}
+
+automation "login" {
+  returns = { token = "t" }
+}
+`,
+				"util": `automation "login" {}`,
+			})).To(BeEmpty())
+		})
+
+		It("reports names which are ambiguous outside of the package", func() {
+			Expect(validatePackages(map[string]string{
+				"": `
+automation "main" {
+  flow "login" {}
+}
+`,
+				"shared": `automation "login" {}`,
+				"util":   `automation "login" {}`,
+			})).To(diagnostic("Unresolved flow", `automation "login" is ambiguous`))
+		})
+	})
+
+	It("skips uncaptured values when the returns of a flow are not known", func() {
+		Expect(validate(`
+automation "main" {
+  flow "login" {}
+
+  navigate {
+    url = greeting
+  }
+}
+
+automation "login" {
+  returns = merge({}, {})
+}
+`)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Variable is an input variable, referenced in expressions as var.NAME
+type Variable struct {
+	Name        string
+	Description string
+
+	// Type is the type constraint, which is cty.DynamicPseudoType when any
+	// type is allowed.
+	Type cty.Type
+
+	// Default is the default value, or nil when the variable is required.
+	Default Expression
+}
+
+// Convert converts the given value to the type of the variable. A string value
+// given for a variable of a collection or structural type is parsed as an
+// expression, which supports values that originate on the command line.
+func (v *Variable) Convert(value cty.Value) (cty.Value, error) {
+	ty := v.typeConstraint()
+	if value.Type() == cty.String && !ty.IsPrimitiveType() && ty != cty.DynamicPseudoType {
+		expr, diags := hclsyntax.ParseExpression([]byte(value.AsString()), v.Name, hcl.InitialPos)
+		if diags.HasErrors() {
+			return cty.NilVal, diags
+		}
+		var valueDiags hcl.Diagnostics
+		value, valueDiags = expr.Value(nil)
+		if valueDiags.HasErrors() {
+			return cty.NilVal, valueDiags
+		}
+	}
+
+	res, err := convert.Convert(value, ty)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("invalid value for variable %q: %w", v.Name, err)
+	}
+	return res, nil
+}
+
+// DefaultValue evaluates the default value of the variable. An error is
+// returned when the variable has no default.
+func (v *Variable) DefaultValue() (cty.Value, error) {
+	if v.Default == nil {
+		return cty.NilVal, fmt.Errorf("no value for required variable %q", v.Name)
+	}
+	value, err := v.Default.Value(nil)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return v.Convert(value)
+}
+
+func (v *Variable) typeConstraint() cty.Type {
+	if v.Type == cty.NilType {
+		return cty.DynamicPseudoType
+	}
+	return v.Type
+}
+
+func variableFromConfig(cfg *config.Variable) *Variable {
+	if cfg == nil {
+		return nil
+	}
+	return &Variable{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Type:        cfg.Type,
+		Default:     ExpressionFromHCL(cfg.Default),
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variable", func() {
+
+	Describe("New", func() {
+
+		It("collects variables from each file", func() {
+			m := model.New(
+				&config.File{Variables: []*config.Variable{{Name: "a", Type: cty.String}}},
+				&config.File{Variables: []*config.Variable{{Name: "b", Type: cty.Number}}},
+			)
+
+			Expect(m.Variables).To(HaveLen(2))
+			Expect(m.Variable("b").Type).To(Equal(cty.Number))
+			Expect(m.Variable("c")).To(BeNil())
+		})
+	})
+
+	Describe("Convert", func() {
+
+		DescribeTable("examples",
+			func(ty cty.Type, in cty.Value, expected cty.Value) {
+				v := &model.Variable{Name: "v", Type: ty}
+				actual, err := v.Convert(in)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual.Equals(expected).True()).To(BeTrue())
+			},
+			Entry("string", cty.String, cty.StringVal("s"), cty.StringVal("s")),
+			Entry("string to number", cty.Number, cty.StringVal("42"), cty.NumberIntVal(42)),
+			Entry("string to bool", cty.Bool, cty.StringVal("true"), cty.True),
+			Entry("parses string as list expression", cty.List(cty.String), cty.StringVal(`["a", "b"]`),
+				cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})),
+			Entry("any type", cty.DynamicPseudoType, cty.StringVal("s"), cty.StringVal("s")),
+		)
+
+		It("returns an error for an invalid value", func() {
+			v := &model.Variable{Name: "v", Type: cty.Number}
+			_, err := v.Convert(cty.StringVal("nope"))
+
+			Expect(err).To(MatchError(ContainSubstring(`invalid value for variable "v"`)))
+		})
+	})
+
+	Describe("DefaultValue", func() {
+
+		It("evaluates the default", func() {
+			expr, _ := hclsyntax.ParseExpression([]byte(`"1"`), "", hcl.InitialPos)
+			v := &model.Variable{Name: "v", Type: cty.Number, Default: model.ExpressionFromHCL(expr)}
+			actual, err := v.DefaultValue()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual.Equals(cty.NumberIntVal(1)).True()).To(BeTrue())
+		})
+
+		It("returns an error when required", func() {
+			v := &model.Variable{Name: "v"}
+			_, err := v.DefaultValue()
+
+			Expect(err).To(MatchError(`no value for required variable "v"`))
+		})
+	})
+})
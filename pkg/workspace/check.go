@@ -5,26 +5,50 @@
 package workspace
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/Carbonfrost/autogun/pkg/config"
+	"github.com/Carbonfrost/autogun/pkg/config/diagnostics"
+	"github.com/Carbonfrost/autogun/pkg/internal/build"
+	internalcli "github.com/Carbonfrost/autogun/pkg/internal/cli"
+	"github.com/Carbonfrost/autogun/pkg/model"
+	"github.com/Carbonfrost/autogun/pkg/model/lint"
 	cli "github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclparse"
 	"golang.org/x/term"
 )
 
 type CheckParams struct {
-	Files *cli.FileSet
+	Files  *cli.FileSet
+	Format internalcli.DiagnosticFormat
+	Lint   bool
 }
 
+// Exit codes of the check command
+const (
+	// checkFoundErrors indicates that the files contain errors. Warnings do
+	// not cause a non-zero exit code.
+	checkFoundErrors = 1
+
+	// checkFailed indicates that the files could not be checked, such as
+	// when they cannot be read
+	checkFailed = 2
+)
+
+// Check returns an action which checks files for errors. Diagnostics are
+// written as text to stderr, or as JSON or SARIF to stdout. The exit code is
+// 0 when there are no errors, 1 when the files contain errors, and 2 when
+// the files could not be checked. Lint rules are also checked when
+// requested, which report warnings that do not affect the exit code.
 func Check() cli.Action {
 	return cli.Pipeline(
 		&cli.Prototype{
 			Name:     "check",
-			HelpText: "Parse files to look for syntax errors",
+			HelpText: "Parse and validate files to look for errors and unresolved references",
 		},
 		bind.Call(checkSpec, useCheckParams()),
 	)
@@ -41,10 +65,24 @@ func useCheckParams() bind.ActionBinder[CheckParams] {
 					Uses:  cli.Accessory("recursive", (*cli.FileSet).RecursiveFlag, cli.HelpText("format directories recursively")),
 				},
 			}...),
+			cli.AddFlags([]*cli.Flag{
+				{
+					Name:     "format",
+					Value:    new(internalcli.DiagnosticFormat),
+					HelpText: "write diagnostics in the specified {FORMAT} (text, json, sarif)",
+				},
+				{
+					Name:     "lint",
+					Value:    new(bool),
+					HelpText: "also check automations using the lint rules enabled by the workspace",
+				},
+			}...),
 		),
 		bind.Func[CheckParams](func(c *cli.Context) (CheckParams, error) {
 			return CheckParams{
-				Files: c.FileSet("files"),
+				Files:  c.FileSet("files"),
+				Format: *c.Value("format").(*internalcli.DiagnosticFormat),
+				Lint:   c.Bool("lint"),
 			}, nil
 		}),
 	)
@@ -52,37 +90,92 @@ func useCheckParams() bind.ActionBinder[CheckParams] {
 
 func checkSpec(c CheckParams) error {
 	var anyErrors bool
-	parser := hclparse.NewParser()
-	color := term.IsTerminal(int(os.Stderr.Fd()))
-	w, _, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		w = 80
-	}
-	diagWriter := hcl.NewDiagnosticTextWriter(os.Stderr, parser.Files(), uint(w), color)
+	parser := config.NewParser(nil)
+	diagWriter := newDiagnosticWriter(c.Format, parser.Files())
 	files, err := enumerateFiles(c.Files)
 	if err != nil {
-		return err
+		return cli.Exit(err.Error(), checkFailed)
 	}
 
+	decoded := make([]*config.File, 0, len(files))
 	for _, path := range files {
 		data, err := os.ReadFile(path)
 		if err != nil {
-			return unableToCheck(path, err)
+			return cli.Exit(unableToCheck(path, err).Error(), checkFailed)
 		}
 
-		_, diags := parser.ParseHCL(data, path)
+		file, diags := parser.ParseSource(path, data)
 		diagWriter.WriteDiagnostics(diags)
 
 		if diags.HasErrors() {
 			anyErrors = true
 		}
+		decoded = append(decoded, file)
+	}
+
+	// Flows can refer to automations of the packages which the files import
+	all, err := resolveImports("", decoded)
+	if err != nil {
+		var importDiags hcl.Diagnostics
+		if !errors.As(err, &importDiags) {
+			return cli.Exit(err.Error(), checkFailed)
+		}
+		diagWriter.WriteDiagnostics(importDiags)
+		anyErrors = true
+		all = decoded
+	}
+
+	// References to named selectors can be declared in any of the files
+	diags := config.ResolveSelectors(all...)
+	diagWriter.WriteDiagnostics(diags)
+	if diags.HasErrors() {
+		anyErrors = true
+	}
+
+	diags = model.Validate(all...)
+	diagWriter.WriteDiagnostics(diags)
+	if diags.HasErrors() {
+		anyErrors = true
+	}
+
+	// Lint rules only report warnings, so they do not affect the exit code.
+	// They use the same files as validation so that the flows of imported
+	// packages are known to run the automations of the workspace.
+	if c.Lint && !anyErrors {
+		diagWriter.WriteDiagnostics(lint.Lint(parser.Files(), all...))
+	}
+
+	if err := diagWriter.Flush(); err != nil {
+		return cli.Exit(err.Error(), checkFailed)
 	}
 	if anyErrors {
-		return cli.Exit("one or more files contained errors", 1)
+		return cli.Exit("one or more files contained errors", checkFoundErrors)
 	}
 	return nil
 }
 
+// newDiagnosticWriter creates the writer for the format. Text is written to
+// stderr for people whereas other formats are written to stdout for tools.
+func newDiagnosticWriter(format internalcli.DiagnosticFormat, files map[string]*hcl.File) diagnostics.Writer {
+	switch format {
+	case internalcli.JSONFormat:
+		return diagnostics.NewJSONWriter(os.Stdout)
+	case internalcli.SARIFFormat:
+		return diagnostics.NewSARIFWriter(os.Stdout, diagnostics.Tool{
+			Name:           "autogun",
+			Version:        build.Version.Version,
+			InformationURI: "https://github.com/Carbonfrost/autogun",
+		})
+	}
+
+	color := term.IsTerminal(int(os.Stderr.Fd()))
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		w = 80
+	}
+	return diagnostics.NewTextWriter(os.Stderr, files, uint(w), color)
+}
+
 func unableToCheck(path string, err error) error {
 	return fmt.Errorf("unable to check %s: %w", path, err)
 }
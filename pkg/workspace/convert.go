@@ -0,0 +1,105 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workspace
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Carbonfrost/autogun/pkg/config/format"
+	cli "github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/hashicorp/hcl/v2"
+)
+
+type ConvertParams struct {
+	Files *cli.FileSet
+	Write bool
+}
+
+// Convert returns an action which converts files between the native syntax
+// and the JSON syntax. Files which end in .json are converted to the native
+// syntax, and other files are converted to the JSON syntax.
+func Convert(paramsopt ...ConvertParams) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "convert",
+			HelpText: "Convert files between the native and JSON syntax",
+		},
+		bind.Call(convertSpec, useConvertParams()),
+	)
+}
+
+func useConvertParams() bind.ActionBinder[ConvertParams] {
+	return bind.NewActionBinder(
+		cli.Pipeline(
+			cli.AddArgs([]*cli.Arg{
+				{
+					Name:  "files",
+					Value: new(cli.FileSet),
+					NArg:  cli.TakeUntilNextFlag,
+					Uses:  cli.Accessory("recursive", (*cli.FileSet).RecursiveFlag, cli.HelpText("convert directories recursively")),
+				},
+			}...),
+			cli.AddFlags([]*cli.Flag{
+				{
+					Name:     "write",
+					Aliases:  []string{"w"},
+					Value:    new(bool),
+					HelpText: "write each converted file next to its source instead of writing to stdout",
+				},
+			}...),
+		),
+		bind.Func[ConvertParams](func(c *cli.Context) (ConvertParams, error) {
+			return ConvertParams{
+				Files: c.FileSet("files"),
+				Write: c.Bool("write"),
+			}, nil
+		}),
+	)
+}
+
+func convertSpec(p ConvertParams) error {
+	files, err := enumerateFiles(p.Files)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		if err := convertFile(path, p.Write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertFile(fn string, write bool) error {
+	inSrc, err := os.ReadFile(fn)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", fn, err)
+	}
+
+	var outSrc []byte
+	if format.IsJSON(fn) {
+		outSrc, err = format.ToHCL(inSrc)
+	} else {
+		var diags hcl.Diagnostics
+		if outSrc, diags = format.ToJSON(fn, inSrc); diags.HasErrors() {
+			err = diags
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %s", fn, err)
+	}
+
+	if write {
+		out := format.ConvertedName(fn)
+		fmt.Fprintf(os.Stderr, "%s\n", out)
+		return os.WriteFile(out, outSrc, 0644)
+	}
+
+	_, err = os.Stdout.Write(outSrc)
+	return err
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
 	"github.com/Carbonfrost/joe-cli/extensions/expr"
 	"github.com/Carbonfrost/joe-cli/extensions/structure"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ScreenshotArgs struct {
@@ -27,6 +30,12 @@ type ScreenshotArgs struct {
 	AtLeast       *int             `mapstructure:"at_least"`
 }
 
+type OptionsArgs struct {
+	RetryInterval *time.Duration `mapstructure:"retry_interval"`
+	AtLeast       *int           `mapstructure:"at_least"`
+	Timeout       *time.Duration `mapstructure:"timeout"`
+}
+
 func Exprs() []*expr.Expr {
 	return []*expr.Expr{
 		{
@@ -69,7 +78,7 @@ func Exprs() []*expr.Expr {
 		},
 		{
 			Name:     "flow", // -flow NAME
-			HelpText: "run an automation by NAME",
+			HelpText: "run an automation by NAME or @PACKAGE/NAME",
 			Args: []*cli.Arg{
 				{
 					Name:  "name",
@@ -185,30 +194,133 @@ func Exprs() []*expr.Expr {
 			HelpText: "store the inner HTML of the selected element",
 			Evaluate: InnerHTML("inner_html"),
 		},
+		{
+			Name:     "outer_html", // -outer_html
+			HelpText: "store the outer HTML of the selected element",
+			Evaluate: OuterHTML("outer_html"),
+		},
+		{
+			Name:     "text", // -text
+			HelpText: "store the text content of the selected element",
+			Evaluate: Text("text"),
+		},
+		{
+			Name:     "attr", // -attr NAME
+			HelpText: "store the value of the attribute {NAME} of the selected element",
+			Args: []*cli.Arg{
+				{
+					Name:  "name",
+					Value: new(string),
+					NArg:  1,
+				},
+			},
+			Evaluate: expr.BindEvaluator(Attribute, bind.String("name")),
+		},
+		{
+			Name:     "value", // -value
+			HelpText: "store the value of the selected form element",
+			Evaluate: Value("value"),
+		},
+		{
+			Name:     "count", // -count
+			HelpText: "store the number of selected elements",
+			Evaluate: Count("count"),
+		},
+		{
+			Name:     "assert", // -assert CONDITION
+			HelpText: "fail unless the HCL {CONDITION} is true",
+			Args: []*cli.Arg{
+				{
+					Name:  "condition",
+					Value: new(string),
+					NArg:  1,
+				},
+			},
+			Evaluate: expr.BindEvaluator(Assert, bind.String("condition")),
+		},
+		{
+			Name:     "assert_title", // -assert_title TITLE
+			HelpText: "fail unless the title of the current page is {TITLE}",
+			Args: []*cli.Arg{
+				{
+					Name:  "title",
+					Value: new(string),
+					NArg:  1,
+				},
+			},
+			Evaluate: expr.BindEvaluator(AssertTitle, bind.String("title")),
+		},
+		{
+			Name:     "assert_text", // -assert_text TEXT
+			HelpText: "fail unless the text of the selected element contains {TEXT}",
+			Args: []*cli.Arg{
+				{
+					Name:  "text",
+					Value: new(string),
+					NArg:  1,
+				},
+			},
+			Evaluate: expr.BindEvaluator(AssertText, bind.String("text")),
+		},
+		{
+			Name:     "assert_count", // -assert_count COUNT
+			HelpText: "fail unless the number of selected elements is {COUNT}",
+			Args: []*cli.Arg{
+				{
+					Name:  "count",
+					Value: new(int),
+					NArg:  1,
+				},
+			},
+			Evaluate: expr.BindEvaluator(AssertCount, bind.Value[int]("count")),
+		},
+		{
+			Name:     "assert_attribute", // -assert_attribute NAME[=VALUE]
+			HelpText: "fail unless the selected element has the attribute {NAME}, optionally with {VALUE}",
+			Args: []*cli.Arg{
+				{
+					Name:      "attribute",
+					Value:     new(string),
+					NArg:      1,
+					UsageText: "NAME[=VALUE]",
+				},
+			},
+			Evaluate: expr.BindEvaluator(AssertAttribute, bind.String("attribute")),
+		},
 		{
 			Name:     "version", // -version
 			HelpText: "print out version information",
 			Evaluate: Version(),
 		},
 		{
-			Name:     "options", // -options retry_interval=TIME,at_least=1
+			Name:     "options", // -options retry_interval=TIME,at_least=1,timeout=TIME
 			Aliases:  []string{"O"},
 			HelpText: "specify options for handling selector",
 			Args: []*cli.Arg{
 				{
 					Name:      "value",
-					Value:     structure.Of(new(model.Options)),
-					UsageText: "{retry_interval=TIME,at_least=NUM}",
+					Value:     structure.Of(new(OptionsArgs)),
+					UsageText: "{retry_interval=TIME,at_least=NUM,timeout=TIME}",
 				},
 			},
-			Evaluate: expr.BindEvaluator(SetOptions, bind.Value[model.Options]("value")),
+			Evaluate: expr.BindEvaluator(SetOptions, bind.Value[*OptionsArgs]("value")),
 		},
 	}
 }
 
-func SetOptions(m model.Options) expr.Evaluator {
+func SetOptions(o *OptionsArgs) expr.Evaluator {
+	opts := new(model.Options)
+	if o.RetryInterval != nil {
+		opts.RetryInterval = durationLiteral(*o.RetryInterval)
+	}
+	if o.AtLeast != nil {
+		opts.AtLeast = model.Literal(cty.NumberIntVal(int64(*o.AtLeast)))
+	}
+	if o.Timeout != nil {
+		opts.Timeout = durationLiteral(*o.Timeout)
+	}
 	return withQuery(func(q *AutomationQuery) error {
-		q.Options = &m
+		q.Options = opts
 		return nil
 	})
 }
@@ -225,7 +337,7 @@ func Screenshot(s *ScreenshotArgs) expr.Evaluator {
 	if s.Selector != "" {
 		local = []*model.Selector{
 			{
-				Target: s.Selector,
+				Target: model.Literal(cty.StringVal(s.Selector)),
 				By:     s.By,
 				On:     s.On,
 			},
@@ -241,10 +353,10 @@ func Screenshot(s *ScreenshotArgs) expr.Evaluator {
 		if opts != nil {
 			localOpts = *opts
 			if s.RetryInterval != nil {
-				localOpts.RetryInterval = s.RetryInterval
+				localOpts.RetryInterval = durationLiteral(*s.RetryInterval)
 			}
 			if s.AtLeast != nil {
-				localOpts.AtLeast = s.AtLeast
+				localOpts.AtLeast = model.Literal(cty.NumberIntVal(int64(*s.AtLeast)))
 			}
 		}
 		return &model.Screenshot{
@@ -320,7 +432,7 @@ func Flow(name string) expr.Evaluator {
 
 func Eval(script string) expr.Evaluator {
 	return wrapTaskAsEvaluator(&model.Eval{
-		Script: script,
+		Script: model.Literal(cty.StringVal(script)),
 		Name:   "_1",
 	})
 }
@@ -334,7 +446,7 @@ func NavigateBack() expr.Evaluator {
 }
 
 func Sleep(d time.Duration) expr.Evaluator {
-	return wrapTaskAsEvaluator(&model.Sleep{Duration: d})
+	return wrapTaskAsEvaluator(&model.Sleep{Duration: durationLiteral(d)})
 }
 
 func Reload() expr.Evaluator {
@@ -355,6 +467,90 @@ func InnerHTML(name string) expr.Evaluator {
 	})
 }
 
+func OuterHTML(name string) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.OuterHTML{Name: name, Selectors: selectors, Options: opts}
+	})
+}
+
+func Text(name string) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Text{Name: name, Selectors: selectors, Options: opts}
+	})
+}
+
+// Attribute stores the value of the attribute under its name
+func Attribute(name string) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Attribute{Name: name, Attribute: name, Selectors: selectors, Options: opts}
+	})
+}
+
+func Value(name string) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Value{Name: name, Selectors: selectors, Options: opts}
+	})
+}
+
+func Count(name string) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Count{Name: name, Selectors: selectors, Options: opts}
+	})
+}
+
+func Assert(condition string) expr.Evaluator {
+	condExp, diags := hclsyntax.ParseExpression([]byte(condition), "-", hcl.Pos{})
+	return withAutomation(func(a *model.Automation) error {
+		if diags.HasErrors() {
+			return diags
+		}
+		appendTask(a, &model.Assert{Condition: model.ExpressionFromHCL(condExp)})
+		return nil
+	})
+}
+
+func AssertTitle(title string) expr.Evaluator {
+	titleExp, _ := parseHCL(title)
+	return wrapTaskAsEvaluator(&model.Assert{Title: model.ExpressionFromHCL(titleExp)})
+}
+
+func AssertText(text string) expr.Evaluator {
+	textExp, _ := parseHCL(text)
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Assert{
+			TextContains: model.ExpressionFromHCL(textExp),
+			Selectors:    selectors,
+			Options:      opts,
+		}
+	})
+}
+
+func AssertCount(count int) expr.Evaluator {
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Assert{
+			Count:     model.Literal(cty.NumberIntVal(int64(count))),
+			Selectors: selectors,
+			Options:   opts,
+		}
+	})
+}
+
+func AssertAttribute(attribute string) expr.Evaluator {
+	name, value, hasValue := strings.Cut(attribute, "=")
+	var valueExp hcl.Expression
+	if hasValue {
+		valueExp, _ = parseHCL(value)
+	}
+	return wrapSelectorTask(func(selectors []*model.Selector, opts *model.Options) model.Task {
+		return &model.Assert{
+			Attribute: name,
+			Value:     model.ExpressionFromHCL(valueExp),
+			Selectors: selectors,
+			Options:   opts,
+		}
+	})
+}
+
 func Version() expr.Evaluator {
 	return wrapTaskAsEvaluator(&model.Version{})
 }
@@ -394,6 +590,10 @@ func wrapSelectorTask(build func(selectors []*model.Selector, opts *model.Option
 	})
 }
 
+func durationLiteral(d time.Duration) model.Expression {
+	return model.Literal(cty.StringVal(d.String()))
+}
+
 func appendTask(a *model.Automation, t model.Task) {
 	a.Tasks = append(a.Tasks, t)
 }
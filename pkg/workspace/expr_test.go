@@ -13,6 +13,7 @@ import (
 	"github.com/Carbonfrost/joe-cli/extensions/expr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var _ = Describe("Exprs", func() {
@@ -29,9 +30,16 @@ var _ = Describe("Exprs", func() {
 		Expect(names).To(ContainElement(name))
 	},
 		EntryDescription("%[1]s"),
+		Entry(nil, "assert"),
+		Entry(nil, "assert_attribute"),
+		Entry(nil, "assert_count"),
+		Entry(nil, "assert_text"),
+		Entry(nil, "assert_title"),
+		Entry(nil, "attr"),
 		Entry(nil, "blur"),
 		Entry(nil, "clear"),
 		Entry(nil, "click"),
+		Entry(nil, "count"),
 		Entry(nil, "doubleclick"),
 		Entry(nil, "eval"),
 		Entry(nil, "inner_html"),
@@ -39,13 +47,16 @@ var _ = Describe("Exprs", func() {
 		Entry(nil, "back"),
 		Entry(nil, "forward"),
 		Entry(nil, "options"),
+		Entry(nil, "outer_html"),
 		Entry(nil, "reload"),
 		Entry(nil, "screenshot"),
 		Entry(nil, "select"),
 		Entry(nil, "send_keys"),
 		Entry(nil, "sleep"),
 		Entry(nil, "stop"),
+		Entry(nil, "text"),
 		Entry(nil, "title"),
+		Entry(nil, "value"),
 		Entry(nil, "version"),
 		Entry(nil, "wait_visible"),
 	)
@@ -86,7 +97,7 @@ var _ = Describe("select propagation", func() {
 		Expect(tasks).To(HaveLen(1))
 		Expect(tasks[0]).To(Equal(&model.Click{
 			Selectors: []*model.Selector{
-				{Target: "button", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("button")), By: model.ByQueryAll},
 			},
 		}))
 	})
@@ -94,7 +105,7 @@ var _ = Describe("select propagation", func() {
 	It("propagates the selector set to each subsequent task", func() {
 		tasks := evaluate("-select", "input", "-clear", "-click", "-blur")
 		Expect(tasks).To(HaveLen(3))
-		want := []*model.Selector{{Target: "input", By: model.ByQueryAll}}
+		want := []*model.Selector{{Target: model.Literal(cty.StringVal("input")), By: model.ByQueryAll}}
 		Expect(tasks[0]).To(Equal(&model.Clear{Selectors: want}))
 		Expect(tasks[1]).To(Equal(&model.Click{Selectors: want}))
 		Expect(tasks[2]).To(Equal(&model.Blur{Selectors: want}))
@@ -105,8 +116,8 @@ var _ = Describe("select propagation", func() {
 		Expect(tasks).To(HaveLen(1))
 		Expect(tasks[0]).To(Equal(&model.WaitVisible{
 			Selectors: []*model.Selector{
-				{Target: "a", By: model.ByQueryAll},
-				{Target: "b", By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("a")), By: model.ByQueryAll},
+				{Target: model.Literal(cty.StringVal("b")), By: model.ByQueryAll},
 			},
 		}))
 	})
@@ -115,10 +126,10 @@ var _ = Describe("select propagation", func() {
 		tasks := evaluate("-select", "first", "-click", "-select", "second", "-click")
 		Expect(tasks).To(HaveLen(2))
 		Expect(tasks[0]).To(Equal(&model.Click{
-			Selectors: []*model.Selector{{Target: "first", By: model.ByQueryAll}},
+			Selectors: []*model.Selector{{Target: model.Literal(cty.StringVal("first")), By: model.ByQueryAll}},
 		}))
 		Expect(tasks[1]).To(Equal(&model.Click{
-			Selectors: []*model.Selector{{Target: "second", By: model.ByQueryAll}},
+			Selectors: []*model.Selector{{Target: model.Literal(cty.StringVal("second")), By: model.ByQueryAll}},
 		}))
 	})
 
@@ -127,7 +138,7 @@ var _ = Describe("select propagation", func() {
 		Expect(tasks).To(HaveLen(1))
 		shot := tasks[0].(*model.Screenshot)
 		Expect(shot.Selectors).To(Equal([]*model.Selector{
-			{Target: "local"},
+			{Target: model.Literal(cty.StringVal("local"))},
 		}))
 	})
 
@@ -136,7 +147,7 @@ var _ = Describe("select propagation", func() {
 		Expect(tasks).To(HaveLen(1))
 		shot := tasks[0].(*model.Screenshot)
 		Expect(shot.Selectors).To(Equal([]*model.Selector{
-			{Target: "fromset", By: model.ByQueryAll},
+			{Target: model.Literal(cty.StringVal("fromset")), By: model.ByQueryAll},
 		}))
 	})
 })
@@ -10,15 +10,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/Carbonfrost/autogun/pkg/config"
 	"github.com/Carbonfrost/autogun/pkg/config/format"
 	cli "github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/hashicorp/hcl/v2"
 )
 
 type FormatParams struct {
 	Files *cli.FileSet
 	Write bool
+	List  bool
+	Diff  bool
 }
 
 // Fmt returns an action which formats source. The parameters
@@ -54,38 +59,69 @@ func useFormatParams() bind.ActionBinder[FormatParams] {
 					Value:    new(bool),
 					HelpText: "update source files in-place instead of writing to stdout",
 				},
+				{
+					Name:     "list",
+					Aliases:  []string{"l"},
+					Value:    new(bool),
+					HelpText: "list files whose formatting differs instead of writing to stdout",
+				},
+				{
+					Name:     "diff",
+					Aliases:  []string{"d"},
+					Value:    new(bool),
+					HelpText: "display diffs instead of rewriting files",
+				},
 			}...),
 		),
 		bind.Func[FormatParams](func(c *cli.Context) (FormatParams, error) {
 			return FormatParams{
 				Files: c.FileSet("files"),
 				Write: c.Bool("write"),
+				List:  c.Bool("list"),
+				Diff:  c.Bool("diff"),
 			}, nil
 		}),
 	)
 }
 
 func formatSpec(f FormatParams) error {
-	overwrite := f.Write
-
 	files, err := enumerateFiles(f.Files)
 	if err != nil {
 		return err
 	}
 
-	return processFiles(overwrite, files)
+	return processFiles(f, files)
 }
 
-func processFiles(overwrite bool, files []string) error {
+func processFiles(f FormatParams, files []string) error {
 	if len(files) == 0 {
-		if overwrite {
+		if f.Write {
 			return errors.New("error: cannot use -w without source filenames")
 		}
 
-		_, err := processFile("<stdin>", os.Stdin, false)
+		settings, diags := loadFormatSettings(".")
+		if diags.HasErrors() {
+			return diags
+		}
+		_, err := processFile(f, "<stdin>", os.Stdin, settings)
 		return err
 	}
 
+	// Settings are loaded before any file is formatted so that files are
+	// not rewritten when the settings of another directory are invalid
+	settings := map[string]*config.Format{}
+	for _, path := range files {
+		pkgDir := filepath.Dir(path)
+		if _, ok := settings[pkgDir]; ok {
+			continue
+		}
+		s, diags := loadFormatSettings(pkgDir)
+		if diags.HasErrors() {
+			return diags
+		}
+		settings[pkgDir] = s
+	}
+
 	var anyChanged bool
 	for _, path := range files {
 		switch dir, err := os.Stat(path); {
@@ -94,9 +130,11 @@ func processFiles(overwrite bool, files []string) error {
 		case dir.IsDir():
 			return fmt.Errorf("can't format directory %s", path)
 		default:
-			changed, err := processFile(path, nil, overwrite)
+			changed, err := processFile(f, path, nil, settings[filepath.Dir(path)])
 			if changed {
-				fmt.Fprintf(os.Stderr, "%s\n", path)
+				if f.Write && !f.List {
+					fmt.Fprintf(os.Stderr, "%s\n", path)
+				}
 				anyChanged = true
 			}
 			if err != nil {
@@ -112,12 +150,16 @@ func processFiles(overwrite bool, files []string) error {
 	return nil
 }
 
-func processFile(fn string, in *os.File, overwrite bool) (changed bool, err error) {
+// processFile formats the file. The result is written to stdout unless the
+// file is rewritten, listed or diffed, in which case changed reports
+// whether its formatting differs.
+func processFile(f FormatParams, fn string, in *os.File, settings *config.Format) (changed bool, err error) {
 	if in == nil {
 		in, err = os.Open(fn)
 		if err != nil {
 			return false, fmt.Errorf("failed to open %s: %s", fn, err)
 		}
+		defer in.Close()
 	}
 
 	inSrc, err := io.ReadAll(in)
@@ -125,17 +167,77 @@ func processFile(fn string, in *os.File, overwrite bool) (changed bool, err erro
 		return false, fmt.Errorf("failed to read %s: %s", fn, err)
 	}
 
-	outSrc := format.Source(inSrc)
-	changed = !bytes.Equal(inSrc, outSrc)
-
-	if overwrite {
-		if changed {
-			return true, os.WriteFile(fn, outSrc, 0644)
+	var outSrc []byte
+	if format.IsJSON(fn) {
+		outSrc, err = format.JSON(inSrc)
+		if err != nil {
+			return false, fmt.Errorf("failed to format %s: %s", fn, err)
 		}
+	} else {
+		outSrc = format.File(fn, inSrc, settings)
+	}
 
+	if !f.Write && !f.List && !f.Diff {
+		_, err = os.Stdout.Write(outSrc)
+		return false, err
+	}
+
+	if bytes.Equal(inSrc, outSrc) {
 		return false, nil
 	}
+	if f.List {
+		fmt.Fprintln(os.Stdout, fn)
+	}
+	if f.Diff {
+		if _, err := os.Stdout.Write(format.Diff(fn+".orig", fn, inSrc, outSrc)); err != nil {
+			return true, err
+		}
+	}
+	if f.Write {
+		return true, os.WriteFile(fn, outSrc, 0644)
+	}
+	return true, nil
+}
+
+// loadFormatSettings gets the format block which is declared by the files in
+// the directory, which is nil when there is none. Only one of the files can
+// declare a format block.
+func loadFormatSettings(dir string) (*config.Format, hcl.Diagnostics) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to load format settings",
+				Detail:   fmt.Sprintf("The directory %q could not be read: %v.", dir, err),
+			},
+		}
+	}
 
-	_, err = os.Stdout.Write(outSrc)
-	return false, err
+	var (
+		res   *config.Format
+		diags hcl.Diagnostics
+		p     = config.NewParser(os.DirFS(dir))
+	)
+	for _, e := range entries {
+		if e.IsDir() || !detectFile(e.Name()) {
+			continue
+		}
+		file, fileDiags := p.LoadFile(e.Name())
+		diags = append(diags, fileDiags...)
+		if file == nil || file.Format == nil {
+			continue
+		}
+		if res != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate format block",
+				Detail:   fmt.Sprintf("The format block was already declared at %s.", res.DeclRange),
+				Subject:  &file.Format.DeclRange,
+			})
+			continue
+		}
+		res = file.Format
+	}
+	return res, diags
 }
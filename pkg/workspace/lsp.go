@@ -0,0 +1,26 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workspace
+
+import (
+	"os"
+
+	"github.com/Carbonfrost/autogun/pkg/lsp"
+	cli "github.com/Carbonfrost/joe-cli"
+)
+
+// LSP returns an action which runs a language server for automation files
+// that speaks the Language Server Protocol over stdin and stdout
+func LSP() cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "lsp",
+			HelpText: "Run a language server for automation files over stdio",
+		},
+		cli.ActionFunc(func(*cli.Context) error {
+			return lsp.NewServer(os.Stdin, os.Stdout).Run()
+		}),
+	)
+}
@@ -7,17 +7,22 @@ package workspace
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Carbonfrost/autogun/pkg/automation"
+	"github.com/Carbonfrost/autogun/pkg/config"
 	"github.com/Carbonfrost/autogun/pkg/model"
 	cli "github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
 	"github.com/Carbonfrost/joe-cli/extensions/expr"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var urlPrefix = []string{
@@ -39,6 +44,12 @@ type AutomationQuery struct {
 type RunParams struct {
 	Expression      *expr.Expression
 	AutomationQuery *AutomationQuery
+
+	// Variables provides the values of input variables set by flags
+	Variables map[string]cty.Value
+
+	// Timeout limits the time that the run can take
+	Timeout time.Duration
 }
 
 func Run() cli.Action {
@@ -55,6 +66,23 @@ func useRunParams() bind.ActionBinder[*RunParams] {
 	return bind.NewActionBinder(
 		cli.Pipeline(
 			FlagsAndArgs(),
+			cli.AddFlags([]*cli.Flag{
+				{
+					Name:     "var",
+					Value:    cli.List(),
+					HelpText: "set the input variable given as {NAME=VALUE}, repeatable",
+				},
+				{
+					Name:     "var-file",
+					Value:    cli.List(),
+					HelpText: "load input variable values from {FILE}, repeatable",
+				},
+				{
+					Name:     "timeout",
+					Value:    new(time.Duration),
+					HelpText: "fail the run when it takes longer than {DURATION}",
+				},
+			}...),
 			cli.AddArgs([]*cli.Arg{
 				{
 					Name:  "sources",
@@ -79,16 +107,30 @@ func useRunParams() bind.ActionBinder[*RunParams] {
 				return nil, err
 			}
 
+			vars, err := loadVariables(c.List("var-file"), c.List("var"))
+			if err != nil {
+				return nil, err
+			}
+
 			return &RunParams{
 				Expression: ensurePrinter(expr.FromContext(c, "expression")),
 				AutomationQuery: &AutomationQuery{
 					Automation: auto,
 				},
+				Variables: vars,
+				Timeout:   c.Duration("timeout"),
 			}, nil
 		}),
 	)
 }
 
+// runResult is the JSON which the run command writes to stdout
+type runResult struct {
+	Outputs  map[string]*json.RawMessage `json:"outputs"`
+	Errors   []string                    `json:"errors,omitempty"`
+	Attempts map[string][]int            `json:"attempts,omitempty"`
+}
+
 func runSpec(ctx *cli.Context, c *RunParams) error {
 	exp := c.Expression
 	err := exp.Evaluate(ctx, c.AutomationQuery)
@@ -106,6 +148,10 @@ func runSpec(ctx *cli.Context, c *RunParams) error {
 		mo,
 		automation.WithProtocol(automation.ProtocolChromedp),
 		automation.WithAllocator(ws.EnsureAllocator()),
+		automation.WithVariables(c.Variables),
+		automation.WithTimeout(c.Timeout),
+		automation.WithBaseDir(ws.Dir()),
+		automation.WithSecrets(ws.SecretProvider()),
 	)
 	if err != nil {
 		return err
@@ -116,8 +162,13 @@ func runSpec(ctx *cli.Context, c *RunParams) error {
 		return err
 	}
 
-	// Generate output from the output variables and persistent files
-	data, _ := json.MarshalIndent(results.Outputs, "", "    ")
+	// Generate output from the output variables, the errors caught by try
+	// blocks, the attempts made by retry blocks and persistent files
+	data, _ := json.MarshalIndent(runResult{
+		Outputs:  results.Outputs,
+		Errors:   results.Errors,
+		Attempts: results.Attempts,
+	}, "", "    ")
 	os.Stdout.Write(data)
 	results.PersistOutputFiles()
 
@@ -151,6 +202,29 @@ func convertSources(c *cli.Context) (*model.Automation, error) {
 	}, nil
 }
 
+// loadVariables reads the values files in order and then applies the
+// NAME=VALUE pairs, so that later values take precedence.
+func loadVariables(files []string, pairs []string) (map[string]cty.Value, error) {
+	res := map[string]cty.Value{}
+	for _, file := range files {
+		p := config.NewParser(os.DirFS(filepath.Dir(file)))
+		values, diags := p.LoadValuesFile(filepath.Base(file))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		maps.Copy(res, values)
+	}
+
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable %q: expected NAME=VALUE", pair)
+		}
+		res[name] = cty.StringVal(value)
+	}
+	return res, nil
+}
+
 func navigate(u string) (model.Task, error) {
 	urlExp, _ := parseHCL(u)
 	return &model.Navigate{
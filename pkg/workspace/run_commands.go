@@ -21,6 +21,7 @@ func FlagsAndArgs() cli.Action {
 	return cli.Pipeline(
 		cli.AddFlags([]*cli.Flag{
 			{Uses: SetBrowserURL()},
+			{Uses: SetProfile()},
 			{Uses: SetProtocol()},
 			{Uses: SetDeviceID()},
 			{Uses: SetExecPath()},
@@ -59,6 +60,16 @@ func setBrowserURLHelper(a *automation.Allocator, source string) error {
 	return a.SetBrowserURL(source)
 }
 
+func SetProfile(v ...string) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "profile",
+			HelpText: "use the browser profile {NAME} declared in the workspace",
+		},
+		withBinding((*automation.Allocator).SetProfile, v...),
+	)
+}
+
 func SetProtocol(v ...automation.Protocol) cli.Action {
 	return cli.Pipeline(
 		&cli.Prototype{
@@ -76,7 +87,7 @@ func SetDeviceID(v ...string) cli.Action {
 		&cli.Prototype{
 			Name:     "device",
 			Aliases:  []string{"D"},
-			HelpText: "use the specified device {ID}",
+			HelpText: "emulate the device {ID}, or a device given as width=W,height=H,mobile,...",
 		},
 		withBinding((*automation.Allocator).SetDeviceID, v...),
 	)
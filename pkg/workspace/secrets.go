@@ -0,0 +1,137 @@
+// Copyright 2026 The Autogun Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Carbonfrost/autogun/pkg/automation"
+	cli "github.com/Carbonfrost/joe-cli"
+	"golang.org/x/term"
+)
+
+// Secrets returns an action which groups the commands that manage the
+// encrypted secrets file of the workspace. The passphrase of the file is
+// provided by the AUTOGUN_SECRETS_KEY environment variable.
+func Secrets() cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "secrets",
+			HelpText: "Manage the encrypted secrets file of the workspace",
+		},
+	)
+}
+
+// SetSecret returns an action which stores a secret in the encrypted secrets
+// file, creating the file when it does not exist. The value is read from
+// stdin so that it does not appear in the arguments of the command.
+func SetSecret() cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "set",
+			HelpText: "Store a secret in the encrypted secrets file, reading its value from stdin",
+		},
+		cli.AddArgs([]*cli.Arg{
+			{
+				Name:  "name",
+				Value: new(string),
+			},
+		}...),
+		cli.ActionFunc(func(c *cli.Context) error {
+			value, err := readSecretValue(os.Stdin)
+			if err != nil {
+				return err
+			}
+			return updateSecrets(FromContext(c).SecretsFile(), func(secrets map[string]string) error {
+				secrets[c.String("name")] = value
+				return nil
+			})
+		}),
+	)
+}
+
+// RemoveSecret returns an action which removes a secret from the encrypted
+// secrets file
+func RemoveSecret() cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "remove",
+			HelpText: "Remove a secret from the encrypted secrets file",
+		},
+		cli.AddArgs([]*cli.Arg{
+			{
+				Name:  "name",
+				Value: new(string),
+			},
+		}...),
+		cli.ActionFunc(func(c *cli.Context) error {
+			name := c.String("name")
+			return updateSecrets(FromContext(c).SecretsFile(), func(secrets map[string]string) error {
+				if _, ok := secrets[name]; !ok {
+					return fmt.Errorf("secret not found %q", name)
+				}
+				delete(secrets, name)
+				return nil
+			})
+		}),
+	)
+}
+
+// updateSecrets decrypts the secrets file, applies fn to its secrets and then
+// encrypts them again. A file which does not exist has no secrets.
+func updateSecrets(path string, fn func(map[string]string) error) error {
+	passphrase := os.Getenv(SecretsKeyEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set to the passphrase of the secrets file", SecretsKeyEnv)
+	}
+
+	secrets, err := automation.ReadSecretsFile(path, passphrase)
+	if errors.Is(err, fs.ErrNotExist) {
+		secrets, err = map[string]string{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := fn(secrets); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return automation.WriteSecretsFile(path, passphrase, secrets)
+}
+
+// readSecretValue reads the value of a secret from the file. When the file is
+// a terminal, the value is prompted for without echoing it. Otherwise, the
+// contents are used without the trailing line ending.
+func readSecretValue(f *os.File) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if fd := int(f.Fd()); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Value: ")
+		data, err = term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+	} else {
+		data, err = io.ReadAll(f)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", errors.New("the value of the secret must not be empty")
+	}
+	return value, nil
+}
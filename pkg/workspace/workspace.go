@@ -10,7 +10,6 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/Carbonfrost/autogun/pkg/automation"
 	"github.com/Carbonfrost/autogun/pkg/config"
@@ -18,8 +17,13 @@ import (
 	"github.com/Carbonfrost/autogun/pkg/model"
 	cli "github.com/Carbonfrost/joe-cli"
 	joeconfig "github.com/Carbonfrost/joe-cli/extensions/config"
+	"github.com/hashicorp/hcl/v2"
 )
 
+// SecretsKeyEnv is the environment variable which provides the passphrase of
+// the encrypted secrets file
+const SecretsKeyEnv = "AUTOGUN_SECRETS_KEY"
+
 type Workspace struct {
 	cli.Action
 
@@ -60,15 +64,19 @@ func (w *Workspace) Pipeline() cli.Action {
 }
 
 // Load scans the workspace for configuration files and builds a Model from
-// the automations they declare.
+// the automations they declare. References to named selectors are resolved
+// once across all of the files, and those which cannot be resolved are
+// returned as diagnostics.
 func (w *Workspace) Load() (*model.Model, error) {
 	files, err := w.loadFiles()
 	if err != nil {
 		return nil, err
 	}
+	if diags := config.ResolveSelectors(files...); diags.HasErrors() {
+		return nil, diags
+	}
 
-	m := model.New(files...)
-	return m, nil
+	return model.New(files...), nil
 }
 
 // Model obtains the model for the workspace. This method implicitly
@@ -94,6 +102,11 @@ func (w *Workspace) AutogunDir() string {
 	return filepath.Join(w.actualDirectory(), ".autogun")
 }
 
+// SecretsFile gets the path of the encrypted secrets file of the workspace
+func (w *Workspace) SecretsFile() string {
+	return filepath.Join(w.AutogunDir(), automation.DefaultSecretsFile)
+}
+
 func (w *Workspace) actualDirectory() string {
 	if w.Directory == "" {
 		res, _ := os.Getwd()
@@ -103,38 +116,142 @@ func (w *Workspace) actualDirectory() string {
 }
 
 func (w *Workspace) loadFiles() ([]*config.File, error) {
-	root := os.DirFS(w.AutogunDir())
-	p := config.NewParser(root)
+	root, files, err := loadPackage(w.AutogunDir(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveImports(root, files)
+}
+
+// loadPackage loads the configuration files in the directory, or the single
+// file, at the path as members of the package. The directory which contains
+// the files is returned.
+func loadPackage(path string, pkg string) (string, []*config.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	root := path
+	if !info.IsDir() {
+		root = filepath.Dir(path)
+	}
+
+	fsys := os.DirFS(root)
+	p := config.NewParser(fsys)
 	files := []*config.File{}
-	err := fs.WalkDir(root, ".", func(path string, info fs.DirEntry, err error) error {
+	load := func(path string) error {
+		file, diag := p.LoadFile(path)
+		if diag.HasErrors() {
+			return diag
+		}
+		file.SetPackage(pkg)
+		files = append(files, file)
+		return nil
+	}
+
+	if !info.IsDir() {
+		return root, files, load(filepath.Base(path))
+	}
+
+	err = fs.WalkDir(fsys, ".", func(path string, info fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
+		if info.IsDir() || !detectFile(path) {
 			return nil
 		}
-		if strings.HasSuffix(path, ".autog") ||
-			strings.HasSuffix(path, ".autogun") ||
-			strings.HasSuffix(path, ".hcl") ||
-			strings.HasSuffix(path, ".autog.json") ||
-			strings.HasSuffix(path, ".autogun.json") ||
-			strings.HasSuffix(path, ".hcl.json") {
-			file, diag := p.LoadFile(path)
-			if diag.HasErrors() {
-				return diag
-			}
-			files = append(files, file)
-		}
-
-		return nil
+		return load(path)
 	})
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+
+	return root, files, nil
+}
+
+// resolveImports loads the packages imported by the files and in turn, the
+// packages which they import. Sources of imports are relative to the
+// directory of the file which contains the import.
+func resolveImports(root string, files []*config.File) ([]*config.File, error) {
+	type pending struct {
+		file *config.File
+		root string
 	}
 
+	var (
+		diags   hcl.Diagnostics
+		sources = map[string]string{}
+		queue   = make([]pending, 0, len(files))
+	)
+	for _, f := range files {
+		queue = append(queue, pending{f, root})
+	}
+
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		dir := filepath.Join(item.root, filepath.Dir(item.file.Name()))
+		for _, imp := range item.file.Imports {
+			source := filepath.Join(dir, imp.Source)
+			if existing, ok := sources[imp.Name]; ok {
+				if existing != source {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Conflicting import",
+						Detail:   fmt.Sprintf("The package %q is already imported from %q.", imp.Name, existing),
+						Subject:  &imp.NameRange,
+					})
+				}
+				continue
+			}
+			sources[imp.Name] = source
+
+			pkgRoot, imported, err := loadPackage(source, imp.Name)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Failed to import package",
+					Detail:   fmt.Sprintf("The package %q could not be loaded from %q: %v.", imp.Name, imp.Source, err),
+					Subject:  &imp.DeclRange,
+				})
+				continue
+			}
+			for _, f := range imported {
+				files = append(files, f)
+				queue = append(queue, pending{f, pkgRoot})
+			}
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
 	return files, nil
 }
 
+// SecretProvider gets the provider of secrets for automations run in the
+// workspace. Secrets are read from environment variables, then the encrypted
+// secrets file in the autogun directory, and then the .netrc file.
+func (w *Workspace) SecretProvider() automation.SecretProvider {
+	netrc := os.Getenv("NETRC")
+	if netrc == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			netrc = filepath.Join(home, ".netrc")
+		}
+	}
+	return automation.SecretProviders(
+		automation.EnvSecretProvider{},
+		&automation.FileSecretProvider{
+			Path:       w.SecretsFile(),
+			Passphrase: os.Getenv(SecretsKeyEnv),
+		},
+		&automation.NetrcSecretProvider{Path: netrc},
+	)
+}
+
 // TODO This should not be API
 func (w *Workspace) EnsureAllocator() *automation.Allocator {
 	if w.Allocator == nil {
@@ -1,8 +1,13 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/Carbonfrost/autogun/pkg/automation"
 	"github.com/Carbonfrost/autogun/pkg/model"
 	"github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
@@ -29,11 +34,21 @@ func ListDevices(detailedopt ...bool) cli.Action {
 			Options:  cli.Exits | cli.NonPersistent,
 			Value:    new(bool),
 		},
-		bind.Call(printDevicesHelper, binder),
+		bind.Call2(printDevicesHelper, bind.FromContext(workspaceDevices), binder),
 	)
 }
 
-func printDevicesHelper(detailed bool) error {
+// workspaceDevices gets the devices which can be emulated, including those
+// declared by the workspace when it can be loaded
+func workspaceDevices(c context.Context) []model.Device {
+	m, err := FromContext(c).Load()
+	if err != nil {
+		return model.Devices()
+	}
+	return m.AllDevices()
+}
+
+func printDevicesHelper(devices []model.Device, detailed bool) error {
 	var printer = func(s model.Device) {
 		fmt.Print(s.ID, "\t", s.Name, "\n")
 	}
@@ -47,8 +62,54 @@ func printDevicesHelper(detailed bool) error {
 		}
 	}
 
-	for _, d := range model.Devices() {
+	for _, d := range devices {
 		printer(d)
 	}
 	return nil
 }
+
+// ListFunctions provides an action which prints out the functions that can be
+// called from expressions. If present, detailedopt controls the verbosity.
+// When not present, a flag --verbose is consulted to control it
+func ListFunctions(detailedopt ...bool) cli.Action {
+	var binder bind.Binder[bool]
+	switch len(detailedopt) {
+	case 1:
+		binder = bind.Exact(detailedopt[0])
+	case 0:
+		binder = bind.Seen("verbose")
+	default:
+		panic("detailedopt must specify only one argument")
+	}
+
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "list-functions",
+			HelpText: "list functions available in expressions",
+			Options:  cli.Exits | cli.NonPersistent,
+			Value:    new(bool),
+		},
+		bind.Call(printFunctionsHelper, binder),
+	)
+}
+
+func printFunctionsHelper(detailed bool) error {
+	funcs := automation.Functions(".", nil)
+	for _, name := range slices.Sorted(maps.Keys(funcs)) {
+		fn := funcs[name]
+		if !detailed {
+			fmt.Print(name, "\t", fn.Description(), "\n")
+			continue
+		}
+
+		params := make([]string, 0, len(fn.Params())+1)
+		for _, p := range fn.Params() {
+			params = append(params, p.Name)
+		}
+		if p := fn.VarParam(); p != nil {
+			params = append(params, p.Name+"...")
+		}
+		fmt.Print(name, "(", strings.Join(params, ", "), ")", "\t", fn.Description(), "\n")
+	}
+	return nil
+}